@@ -3,7 +3,10 @@ module github.com/alexandrebouthinon/terraform-provider-kuzzle
 go 1.16
 
 require (
+	github.com/hashicorp/go-cty v1.4.1-0.20200414143053-d3edf31b6320
+	github.com/hashicorp/go-uuid v1.0.1
 	github.com/hashicorp/terraform-plugin-sdk/v2 v2.6.1
 	github.com/nbio/st v0.0.0-20140626010706-e9e8d9816f32 // indirect
+	golang.org/x/net v0.0.0-20210326060303-6b1517762897
 	gopkg.in/h2non/gock.v1 v1.0.0
 )
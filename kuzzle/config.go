@@ -0,0 +1,729 @@
+package kuzzle
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// runIDEnvVars lists the environment variables checked, in order, to find the current Terraform run id
+var runIDEnvVars = []string{"TF_RUN_ID", "TFC_RUN_ID"}
+
+// kuzzleResponse is the envelope of every Kuzzle API response
+type kuzzleResponse struct {
+	Status int             `json:"status"`
+	Result json.RawMessage `json:"result"`
+	Error  *kuzzleError    `json:"error"`
+}
+
+// kuzzleError is the error part of a Kuzzle API response
+type kuzzleError struct {
+	ID      string `json:"id"`      // Error identifier, e.g. security.token.invalid
+	Code    int    `json:"code"`    // Numeric error code
+	Message string `json:"message"` // Human readable message
+	Status  int    `json:"status"`  // HTTP status of the error
+}
+
+// apiError is returned when Kuzzle answers a request with an error status
+type apiError struct {
+	StatusCode int
+	Message    string
+	ID         string // Kuzzle error identifier, if known
+	Code       int    // Kuzzle error code, if known
+}
+
+func (e *apiError) Error() string {
+	if e.ID != "" {
+		return fmt.Sprintf("Kuzzle API error (%d, %s): %s", e.StatusCode, e.ID, e.Message)
+	}
+
+	return fmt.Sprintf("Kuzzle API error (%d): %s", e.StatusCode, e.Message)
+}
+
+// newAPIError builds the error of a Kuzzle response from its status and body,
+// falling back to the status text when the body holds no Kuzzle error
+func newAPIError(statusCode int, body []byte) *apiError {
+	apiErr := &apiError{StatusCode: statusCode, Message: http.StatusText(statusCode)}
+
+	var response kuzzleResponse
+	if err := json.Unmarshal(body, &response); err != nil || response.Error == nil {
+		return apiErr
+	}
+
+	if response.Error.Message != "" {
+		apiErr.Message = response.Error.Message
+	}
+	apiErr.ID = response.Error.ID
+	apiErr.Code = response.Error.Code
+
+	return apiErr
+}
+
+// isNotFound tells whether err is a Kuzzle "not found" error
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*apiError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// errorDiagnostic turns an error into a diagnostic. Kuzzle errors are summarized by their message,
+// with their identifier and code in the detail, other errors by the given summary.
+func errorDiagnostic(summary string, err error) diag.Diagnostic {
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		return diag.Diagnostic{Severity: diag.Error, Summary: summary, Detail: err.Error()}
+	}
+
+	detail := fmt.Sprintf("%s: Kuzzle answered with status %d", summary, apiErr.StatusCode)
+	if apiErr.ID != "" {
+		detail += fmt.Sprintf(", error %s (code %d)", apiErr.ID, apiErr.Code)
+	}
+
+	return diag.Diagnostic{Severity: diag.Error, Summary: apiErr.Message, Detail: detail + "."}
+}
+
+type Config struct {
+	Endpoint        string // Kuzzle endpoint URL
+	Token           string // API key or JWT
+	Anonymous       bool   // Whether the provider has no credentials, requests being sent as the anonymous user
+	RunID           string // Terraform run identifier sent with every request
+	RunIDHeader     string // Header used to send the run identifier
+	TokenCheckMode  string // How tokens are sent to _checkToken
+	HealthcheckPath string // Route probed to check the connection, the healthcheck route if empty
+	Experimental    bool   // Whether experimental resources and data sources can be used
+	Refresh         string // Default refresh mode of security writes: wait_for or false
+	ServerVersion   string // Version of the Kuzzle server, empty if it could not be fetched
+
+	Headers  map[string]string // Custom headers sent with every request
+	Volatile string            // JSON volatile metadata attached to write requests, if any
+
+	RateLimitMaxWait time.Duration // Maximum total time spent waiting on rate limited requests
+	MaxRetries       int           // Number of times idempotent requests are retried after a transient failure
+
+	client        *http.Client                                     // HTTP client shared by all requests
+	clientOptions clientOptions                                    // Settings the HTTP client was built with, reused by the endpoint overrides
+	overrides     *endpointOverrides                               // Configurations of the endpoint overrides, none if nil
+	logoutOnDone  bool                                             // Whether the sessions opened by the provider are revoked on teardown
+	requests      chan struct{}                                    // Slots of the requests in flight, unlimited if nil
+	credentials   *credentials                                     // Credentials used to log in again when the token expires, if any
+	tokenKind     string                                           // Kind of the token given in the configuration, API key if empty
+	summary       *operationSummary                                // Counters of the operations done during the run
+	backoff       func(attempt int) time.Duration                  // Overrides the delay before each retry, for tests
+	sleep         func(ctx context.Context, d time.Duration) error // Overrides the way retries are delayed, for tests
+}
+
+// String describes the configuration without its token, so that printing it never leaks the token
+func (c Config) String() string {
+	token := ""
+	if c.Token != "" {
+		token = "<redacted>"
+	}
+
+	return fmt.Sprintf("Config{Endpoint: %q, Token: %q, RunID: %q}", c.Endpoint, token, c.RunID)
+}
+
+// GoString is the %#v counterpart of String
+func (c Config) GoString() string {
+	return c.String()
+}
+
+// authMethod tells how requests are authenticated, and with which strategy when logging in
+func (c *Config) authMethod() (method string, strategy string) {
+	switch {
+	case c.credentials != nil:
+		return authMethodLogin, c.credentials.strategy
+	case c.Token != "" && c.tokenKind == tokenKindJWT:
+		return authMethodJWT, ""
+	case c.Token != "":
+		return authMethodAPIKey, ""
+	default:
+		return authMethodAnonymous, ""
+	}
+}
+
+// Refresh modes of write requests
+const (
+	refreshWaitFor = "wait_for" // The request returns once the change is visible to searches
+	refreshFalse   = "false"    // The request returns right away
+)
+
+// refreshQuery returns the query string asking Kuzzle to wait for a write to be visible, if needed.
+// The resource refresh mode takes precedence over the provider one, wait_for being the default.
+func (c *Config) refreshQuery(mode string) string {
+	if mode == "" {
+		mode = c.Refresh
+	}
+
+	if mode == refreshFalse {
+		return ""
+	}
+
+	return "?refresh=" + refreshWaitFor
+}
+
+// credentials are what the provider logged in with
+type credentials struct {
+	mu       sync.Mutex      // Guards the token of the configuration while it is refreshed
+	strategy string          // Authentication strategy
+	body     json.RawMessage // Login body of the strategy
+}
+
+// authToken returns the token currently sent with requests
+func (c *Config) authToken() string {
+	if c.credentials == nil {
+		return c.Token
+	}
+
+	c.credentials.mu.Lock()
+	defer c.credentials.mu.Unlock()
+
+	return c.Token
+}
+
+// reauthenticate logs in again to replace an expired token,
+// unless another request already did it in the meantime
+func (c *Config) reauthenticate(ctx context.Context, expiredToken string) error {
+	c.credentials.mu.Lock()
+	defer c.credentials.mu.Unlock()
+
+	if c.Token != expiredToken {
+		return nil
+	}
+
+	// The expired token must not be sent along with the credentials
+	login := *c
+	login.Token = ""
+	login.credentials = nil
+
+	jwt, err := authenticate(ctx, &login, c.credentials.strategy, c.credentials.body)
+	if err != nil {
+		return err
+	}
+	c.Token = jwt
+
+	return nil
+}
+
+// newRequest builds a request to the given Kuzzle route, with the custom headers and tagged with the Terraform run id
+func (c *Config) newRequest(ctx context.Context, method string, path string, body io.Reader) (*http.Request, error) {
+	routeURL, err := c.routeURL(path)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, routeURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Custom headers come first so that the ones set by the provider take precedence
+	for name, value := range c.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if c.RunIDHeader != "" && c.RunID != "" {
+		req.Header.Set(c.RunIDHeader, c.RunID)
+	}
+
+	return req, nil
+}
+
+// routeURL joins a Kuzzle route, optionally followed by a query string, to the endpoint URL.
+// Routes of WebSocket endpoints are reached over HTTP, Kuzzle serving both protocols on the same port.
+func (c *Config) routeURL(route string) (string, error) {
+	endpoint, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint.Scheme = httpScheme(endpoint.Scheme)
+
+	ref, err := url.Parse(route)
+	if err != nil {
+		return "", err
+	}
+
+	joined := strings.TrimRight(endpoint.EscapedPath(), "/") + "/" + strings.TrimLeft(ref.EscapedPath(), "/")
+	endpoint.Path, err = url.PathUnescape(joined)
+	if err != nil {
+		return "", err
+	}
+	endpoint.RawPath = joined
+	endpoint.RawQuery = ref.RawQuery
+
+	return endpoint.String(), nil
+}
+
+// doRequest sends a request to the given Kuzzle route with the shared HTTP client,
+// on behalf of the authenticated user. The body, if any, is sent as JSON.
+// Idempotent requests are retried with an exponential backoff on connection errors
+// and on 502, 503 and 504 responses, up to MaxRetries times and within the context deadline.
+// When giving up, the outcome of the last attempt is returned.
+func (c *Config) doRequest(ctx context.Context, method string, path string, body interface{}) (*http.Response, error) {
+	return c.sendWithRetries(ctx, method, path, body, isIdempotent(method))
+}
+
+// sendWithRetries sends a request like doRequest, retrying it on transient failures only if it is retryable,
+// which allows retrying the non-idempotent requests that are safe to send again
+func (c *Config) sendWithRetries(ctx context.Context, method string, path string, body interface{}, retryable bool) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, body)
+		if attempt >= c.MaxRetries || !retryable || !isTransient(ctx, resp, err) {
+			return resp, err
+		}
+
+		delay := c.backoffDelay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := c.wait(ctx, delay); err != nil {
+			return nil, err
+		}
+		c.summary.retry()
+	}
+}
+
+// Content types of request bodies
+const (
+	contentTypeJSON   = "application/json"
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// rawBody is a request body sent as is, with its own content type, instead of being encoded as JSON
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+// send sends a single request to the given Kuzzle route, see doRequest
+func (c *Config) send(ctx context.Context, method string, path string, body interface{}) (*http.Response, error) {
+	var reqBody io.Reader
+	contentType := contentTypeJSON
+	switch b := body.(type) {
+	case nil:
+	case rawBody:
+		reqBody = bytes.NewReader(b.data)
+		contentType = b.contentType
+	default:
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := c.newRequest(ctx, method, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", contentTypeJSON)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if token := c.authToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.Volatile != "" && isWriteRequest(method, path) {
+		req.Header.Set(volatileHeader, c.Volatile)
+	}
+
+	if err := c.acquireRequestSlot(ctx); err != nil {
+		return nil, err
+	}
+	c.summary.request()
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		c.releaseRequestSlot()
+		return nil, err
+	}
+
+	// The request is in flight until its response has been read
+	resp.Body = &slotReleasingBody{ReadCloser: resp.Body, release: c.releaseRequestSlot}
+
+	return resp, nil
+}
+
+// volatileHeader is the header Kuzzle reads the volatile metadata of HTTP requests from
+const volatileHeader = "X-Kuzzle-Volatile"
+
+// readActionRoutes are the path segments of the routes that only read data, even though they are not sent with GET
+var readActionRoutes = map[string]bool{
+	"_search":      true,
+	"_count":       true,
+	"_mGet":        true,
+	"_scroll":      true,
+	"_validate":    true,
+	"_checkToken":  true,
+	"_checkRights": true,
+	"_login":       true,
+}
+
+// isWriteRequest tells whether a request to the given route may change data in Kuzzle
+func isWriteRequest(method string, path string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+
+	route := strings.SplitN(path, "?", 2)[0]
+	for _, segment := range strings.Split(route, "/") {
+		if readActionRoutes[segment] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestSlots returns the slots limiting the number of requests in flight to max, none if max is not positive
+func newRequestSlots(max int) chan struct{} {
+	if max <= 0 {
+		return nil
+	}
+
+	return make(chan struct{}, max)
+}
+
+// acquireRequestSlot waits until fewer requests than the limit are in flight, or the context is done
+func (c *Config) acquireRequestSlot(ctx context.Context) error {
+	if c.requests == nil {
+		return nil
+	}
+
+	select {
+	case c.requests <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseRequestSlot frees the slot of a request that is no longer in flight
+func (c *Config) releaseRequestSlot() {
+	if c.requests != nil {
+		<-c.requests
+	}
+}
+
+// slotReleasingBody is a response body releasing the slot of its request when it is closed
+type slotReleasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *slotReleasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+
+	return err
+}
+
+// clientOptions are the settings of the HTTP client shared by all requests
+type clientOptions struct {
+	endpoint           string         // Kuzzle endpoint URL
+	insecureSkipVerify bool           // Skip TLS certificate verification for HTTPS endpoints
+	rootCAs            *x509.CertPool // Certificate authorities trusted for HTTPS endpoints, system ones if nil
+	timeout            time.Duration  // Time limit of each request, none if zero
+	proxy              *url.URL       // Proxy all requests go through, the one of the environment if nil
+}
+
+// newHTTPClient builds the HTTP client shared by all requests.
+// The default transport, which honors the proxy environment variables, is kept
+// unless a proxy or a TLS setting requires a dedicated one.
+func newHTTPClient(options clientOptions) *http.Client {
+	client := &http.Client{Timeout: options.timeout}
+
+	var tlsConfig *tls.Config
+	if isSecureEndpoint(options.endpoint) {
+		if options.insecureSkipVerify {
+			tlsConfig = &tls.Config{InsecureSkipVerify: true}
+		} else if options.rootCAs != nil {
+			tlsConfig = &tls.Config{RootCAs: options.rootCAs}
+		}
+	}
+
+	if tlsConfig != nil || options.proxy != nil {
+		client.Transport = newTransport(tlsConfig, options.proxy)
+	}
+
+	return client
+}
+
+// parseProxyURL parses the URL of a proxy, which must be an HTTP, HTTPS or SOCKS5 proxy
+func parseProxyURL(raw string) (*url.URL, error) {
+	proxy, err := url.Parse(raw)
+	if urlErr, ok := err.(*url.Error); ok {
+		// The URL is left out of the error, as it may hold the proxy credentials
+		return nil, urlErr.Err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q, expected http, https or socks5", proxy.Scheme)
+	}
+
+	if proxy.Host == "" {
+		return nil, fmt.Errorf("no host")
+	}
+
+	return proxy, nil
+}
+
+// newCertPool returns a pool made of the PEM-encoded certificates
+func newCertPool(pem []byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no valid PEM-encoded certificate found")
+	}
+
+	return pool, nil
+}
+
+// newTransport returns a transport with the same settings as http.DefaultTransport, the given TLS configuration
+// and the given proxy, the proxy environment variables being used if it is nil
+func newTransport(tlsConfig *tls.Config, proxy *url.URL) *http.Transport {
+	proxyFunc := http.ProxyFromEnvironment
+	if proxy != nil {
+		proxyFunc = http.ProxyURL(proxy)
+	}
+
+	return &http.Transport{
+		Proxy: proxyFunc,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}
+}
+
+// httpClient returns the shared HTTP client, or the default one if the configuration has none
+func (c *Config) httpClient() *http.Client {
+	if c.client != nil {
+		return c.client
+	}
+
+	return http.DefaultClient
+}
+
+// query sends a JSON request to the given Kuzzle route on behalf of the authenticated user
+// and decodes the "result" part of the response into result, if not nil.
+// Rate limited requests are retried once the delay asked by Kuzzle has elapsed,
+// as long as the total waiting time stays within the rate limit budget.
+// When the token has expired, the provider logs in again, if it has credentials, and retries once.
+func (c *Config) query(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	var waited time.Duration
+	reauthenticated := false
+	for {
+		token := c.authToken()
+		resp, err := c.doRequest(ctx, method, path, body)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && token != "" && !reauthenticated {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+
+			// There is nothing to refresh an API key or a JWT given in the configuration with
+			if c.credentials == nil {
+				kind := c.tokenKind
+				if kind == "" {
+					kind = tokenKindAPIKey
+				}
+				return &apiError{StatusCode: resp.StatusCode, Message: kind + " invalid or expired"}
+			}
+
+			if err := c.reauthenticate(ctx, token); err != nil {
+				return fmt.Errorf("token expired and logging in again failed: %s", err)
+			}
+			reauthenticated = true
+			continue
+		}
+
+		// Without credentials, a refusal means that the operation requires authentication rather than more rights
+		if c.Anonymous && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+			err := decodeResponse(resp, nil)
+			if apiErr, ok := err.(*apiError); ok {
+				apiErr.Message = fmt.Sprintf("this operation requires authentication, set api_key or login credentials in the provider (%s)", apiErr.Message)
+			}
+			return err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+
+			delay := retryAfter(resp.Header.Get("Retry-After"))
+			if waited+delay > c.RateLimitMaxWait {
+				return &apiError{
+					StatusCode: resp.StatusCode,
+					Message:    fmt.Sprintf("rate limited, giving up after waiting %s", waited),
+				}
+			}
+
+			waited += delay
+			if err := c.wait(ctx, delay); err != nil {
+				return err
+			}
+			c.summary.retry()
+			continue
+		}
+
+		return decodeResponse(resp, result)
+	}
+}
+
+// decodeResponse reads and closes a Kuzzle response, then decodes its "result" part into result, if not nil
+func decodeResponse(resp *http.Response, result interface{}) error {
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		return newAPIError(resp.StatusCode, respBody)
+	}
+
+	// Some routes, deletions notably, may answer without any content
+	if resp.StatusCode == http.StatusNoContent || len(bytes.TrimSpace(respBody)) == 0 {
+		return nil
+	}
+
+	var response kuzzleResponse
+	if err := decodeJSON(respBody, &response); err != nil {
+		return fmt.Errorf("Kuzzle returned an invalid response: %s", err)
+	}
+
+	if response.Error != nil {
+		return newAPIError(resp.StatusCode, respBody)
+	}
+
+	if result != nil && len(response.Result) > 0 {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("Kuzzle returned an unexpected result: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// backoffDelay returns the delay before the retry following the given attempt, doubling from half a second up to 30 seconds
+func (c *Config) backoffDelay(attempt int) time.Duration {
+	if c.backoff != nil {
+		return c.backoff(attempt)
+	}
+
+	delay := 500 * time.Millisecond
+	for i := 0; i < attempt && delay < 30*time.Second; i++ {
+		delay *= 2
+	}
+	if delay > 30*time.Second {
+		delay = 30 * time.Second
+	}
+
+	return delay
+}
+
+// isIdempotent tells whether a request with the given method can safely be sent again
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// isTransient tells whether the outcome of a request is a failure that may not happen again,
+// like a connection error or a node being restarted behind a load balancer
+func isTransient(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// wait pauses for the given duration, unless the context is done first
+func (c *Config) wait(ctx context.Context, d time.Duration) error {
+	if c.sleep != nil {
+		return c.sleep(ctx, d)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as an HTTP date.
+// It defaults to one second when the header is missing or invalid.
+func retryAfter(header string) time.Duration {
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+		return 0
+	}
+
+	return time.Second
+}
+
+// resolveRunID returns the Terraform run id found in the environment, or a random UUID if there is none
+func resolveRunID() (string, error) {
+	for _, name := range runIDEnvVars {
+		if runID := os.Getenv(name); runID != "" {
+			return runID, nil
+		}
+	}
+
+	return uuid.GenerateUUID()
+}
@@ -0,0 +1,113 @@
+package kuzzle
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Values of a Kuzzle right, from the most to the least permissive
+const (
+	rightAllowed     = "allowed"     // The action is allowed
+	rightConditional = "conditional" // The action is allowed under conditions set by the role
+	rightDenied      = "denied"      // The action is denied
+)
+
+func dataSourceKuzzleCan() *schema.Resource {
+	return &schema.Resource{
+		Description: "Tells whether the identity of the provider is allowed an API action, e.g. to check it in a precondition",
+
+		ReadContext: dataSourceKuzzleCanRead,
+
+		Schema: map[string]*schema.Schema{
+			"controller": { // API controller
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "API controller, e.g. document",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"action": { // API action
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "API action of the controller, e.g. create",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"index": { // Index the action applies to
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Index the action applies to, any index if not set",
+			},
+			"collection": { // Collection the action applies to
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"index"},
+				Description:  "Collection the action applies to, any collection if not set",
+			},
+			"allowed": { // Whether the action is allowed
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the action is allowed, possibly under conditions",
+			},
+			"value": { // Right value
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Right on the action: allowed, conditional or denied",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleCanRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	wanted := userRight{
+		Controller: d.Get("controller").(string),
+		Action:     d.Get("action").(string),
+		Index:      d.Get("index").(string),
+		Collection: d.Get("collection").(string),
+	}
+
+	var rights struct {
+		Hits []userRight `json:"hits"`
+	}
+	if err := config.query(ctx, http.MethodGet, "/users/_me/_rights", nil, &rights); err != nil {
+		return diag.Errorf("Error reading Kuzzle rights of the provider identity: %s", err)
+	}
+
+	value := rightValue(rights.Hits, wanted)
+
+	d.SetId(strings.Join([]string{wanted.Controller, wanted.Action, wanted.Index, wanted.Collection}, ":"))
+	d.Set("allowed", value != rightDenied)
+	d.Set("value", value)
+
+	return nil
+}
+
+// rightValue returns the most permissive value of the rights covering the wanted action, denied if none does.
+// An empty index or collection stands for all of them, so only the rights given on any index or collection cover it.
+func rightValue(rights []userRight, wanted userRight) string {
+	value := rightDenied
+	for _, right := range rights {
+		if !rightCovers(right.Controller, wanted.Controller) || !rightCovers(right.Action, wanted.Action) ||
+			!rightCovers(right.Index, wanted.Index) || !rightCovers(right.Collection, wanted.Collection) {
+			continue
+		}
+
+		switch right.Value {
+		case rightAllowed:
+			return rightAllowed
+		case rightConditional:
+			value = rightConditional
+		}
+	}
+
+	return value
+}
+
+// rightCovers tells whether the scope of a right, possibly a wildcard, covers the wanted one
+func rightCovers(scope string, wanted string) bool {
+	return scope == "*" || (wanted != "" && scope == wanted)
+}
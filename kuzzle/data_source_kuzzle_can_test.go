@@ -0,0 +1,93 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleCanRead(t *testing.T) {
+	rights := json.RawMessage(`{"result": {"hits": [
+		{"controller": "document", "action": "*", "index": "iot", "collection": "*", "value": "allowed"},
+		{"controller": "document", "action": "delete", "index": "iot", "collection": "sensors", "value": "denied"},
+		{"controller": "document", "action": "update", "index": "nyc-open-data", "collection": "yellow-taxi", "value": "conditional"},
+		{"controller": "server", "action": "info", "index": "*", "collection": "*", "value": "allowed"},
+		{"controller": "security", "action": "*", "index": "*", "collection": "*", "value": "denied"}
+	]}}`)
+
+	tests := []struct {
+		name        string
+		raw         map[string]interface{}
+		wantAllowed bool
+		wantValue   string
+	}{
+		{
+			name:        "Allowed on any collection of the index",
+			raw:         map[string]interface{}{"controller": "document", "action": "create", "index": "iot", "collection": "sensors"},
+			wantAllowed: true,
+			wantValue:   rightAllowed,
+		},
+		{
+			name:        "Most permissive right wins",
+			raw:         map[string]interface{}{"controller": "document", "action": "delete", "index": "iot", "collection": "sensors"},
+			wantAllowed: true,
+			wantValue:   rightAllowed,
+		},
+		{
+			name:        "Other index",
+			raw:         map[string]interface{}{"controller": "document", "action": "create", "index": "nyc-open-data", "collection": "yellow-taxi"},
+			wantAllowed: false,
+			wantValue:   rightDenied,
+		},
+		{
+			name:        "Conditional",
+			raw:         map[string]interface{}{"controller": "document", "action": "update", "index": "nyc-open-data", "collection": "yellow-taxi"},
+			wantAllowed: true,
+			wantValue:   rightConditional,
+		},
+		{
+			name:        "Index restricted right does not cover every index",
+			raw:         map[string]interface{}{"controller": "document", "action": "create"},
+			wantAllowed: false,
+			wantValue:   rightDenied,
+		},
+		{
+			name:        "Action without index",
+			raw:         map[string]interface{}{"controller": "server", "action": "info"},
+			wantAllowed: true,
+			wantValue:   rightAllowed,
+		},
+		{
+			name:        "Denied",
+			raw:         map[string]interface{}{"controller": "security", "action": "createUser"},
+			wantAllowed: false,
+			wantValue:   rightDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.
+				New("http://kuzzle:7512").
+				Get("/users/_me/_rights").
+				MatchHeader("Authorization", "^Bearer myApiKey$").
+				Reply(200).
+				JSON(rights)
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleCan().Schema, tt.raw)
+			diags := dataSourceKuzzleCanRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512", Token: "myApiKey"})
+			if diags.HasError() {
+				t.Fatalf("dataSourceKuzzleCanRead() diags = %v", diags)
+			}
+			if got := d.Get("allowed").(bool); got != tt.wantAllowed {
+				t.Errorf("dataSourceKuzzleCanRead() allowed = %v, want %v", got, tt.wantAllowed)
+			}
+			if got := d.Get("value").(string); got != tt.wantValue {
+				t.Errorf("dataSourceKuzzleCanRead() value = %v, want %v", got, tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceKuzzleCollection() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns the mappings of an existing Kuzzle collection, failing if it does not exist. " +
+			"Kuzzle has no API action returning the storage settings of a collection, they are not available",
+
+		ReadContext: dataSourceKuzzleCollectionRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Collection name",
+			},
+			"mappings": { // Collection mappings
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON mappings of the collection, with their dynamic policy, metadata and properties, with its keys sorted",
+			},
+			"dynamic_policy": { // Handling of unmapped fields
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How the collection handles fields missing from its mappings: true (added to the mappings), false (stored but not indexed) or strict (rejected)",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleCollectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	var mappings map[string]interface{}
+	err := config.query(ctx, http.MethodGet, collectionPath(index, collection)+"/_mapping", nil, &mappings)
+	if isNotFound(err) {
+		return diag.Errorf("Kuzzle collection %s/%s does not exist", index, collection)
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle collection %s/%s mappings: %s", index, collection, err)
+	}
+
+	raw, err := json.Marshal(mappings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	normalized, err := normalizeJSON(raw)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle collection %s/%s mappings: %s", index, collection, err)
+	}
+
+	d.SetId(index + "/" + collection)
+	d.Set("mappings", normalized)
+	d.Set("dynamic_policy", dynamicPolicy(mappings))
+
+	return nil
+}
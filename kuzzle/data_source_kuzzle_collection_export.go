@@ -0,0 +1,166 @@
+package kuzzle
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// scrollPage is a page of documents returned by a scrolled Kuzzle search
+type scrollPage struct {
+	Hits     []searchHit `json:"hits"`
+	Total    int         `json:"total"`
+	ScrollID string      `json:"scrollId"`
+}
+
+func dataSourceKuzzleCollectionExport() *schema.Resource {
+	return &schema.Resource{
+		Description: "Exports all the documents of a Kuzzle collection to a local NDJSON file",
+
+		ReadContext: dataSourceKuzzleCollectionExportRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Collection name",
+			},
+			"path": { // NDJSON file path
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Path of the NDJSON file to write, one {\"_id\": ..., \"body\": {...}} document per line",
+			},
+			"batch_size": { // Number of documents per scroll page
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      500,
+				Description:  "Number of documents fetched by each scroll request",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"scroll": { // Scroll cursor time to live
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "1m",
+				Description: "Time to live of the scroll cursor between two pages",
+			},
+			"exported": { // Number of exported documents
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of exported documents",
+			},
+			"sha256": { // Hash of the written file
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 hash of the written file",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleCollectionExportRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	path := d.Get("path").(string)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return diag.Errorf("Error creating %s: %s", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	writer := bufio.NewWriter(io.MultiWriter(file, hash))
+
+	exported, err := exportDocuments(ctx, config, index, collection, d.Get("batch_size").(int), d.Get("scroll").(string), writer)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		return diag.Errorf("Error exporting Kuzzle collection %s/%s to %s: %s", index, collection, path, err)
+	}
+
+	d.SetId(index + "/" + collection + "/" + path)
+	d.Set("exported", exported)
+	d.Set("sha256", fmt.Sprintf("%x", hash.Sum(nil)))
+
+	return nil
+}
+
+// exportDocuments scrolls through all the documents of the collection and writes them to w as NDJSON,
+// returning the number of written documents
+func exportDocuments(ctx context.Context, config *Config, index string, collection string, batchSize int, scroll string, w io.Writer) (int, error) {
+	exported := 0
+	_, err := scrollSearch(ctx, config, index, collection, map[string]interface{}{}, batchSize, scroll, func(hit searchHit) (bool, error) {
+		line, err := json.Marshal(ndjsonDocument{ID: hit.ID, Body: hit.Source})
+		if err != nil {
+			return false, err
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return false, err
+		}
+		exported++
+
+		return true, nil
+	})
+
+	return exported, err
+}
+
+// scrollSearch runs a scrolled search on the collection and passes each matching document to visit,
+// page after page, until every document has been visited or visit returns false.
+// It returns the total number of matching documents.
+func scrollSearch(ctx context.Context, config *Config, index string, collection string, body interface{}, size int, scroll string, visit func(hit searchHit) (bool, error)) (int, error) {
+	params := url.Values{}
+	params.Set("scroll", scroll)
+	params.Set("size", strconv.Itoa(size))
+	path := "/" + url.PathEscape(index) + "/" + url.PathEscape(collection) + "/_search?" + params.Encode()
+
+	var page scrollPage
+	if err := config.query(ctx, http.MethodPost, path, body, &page); err != nil {
+		return 0, err
+	}
+
+	total := page.Total
+	visited := 0
+	for len(page.Hits) > 0 {
+		for _, hit := range page.Hits {
+			more, err := visit(hit)
+			if err != nil || !more {
+				return total, err
+			}
+			visited++
+		}
+
+		if visited >= total || page.ScrollID == "" {
+			break
+		}
+
+		scrollParams := url.Values{}
+		scrollParams.Set("scroll", scroll)
+		scrollPath := "/_scroll/" + url.PathEscape(page.ScrollID) + "?" + scrollParams.Encode()
+
+		page = scrollPage{}
+		if err := config.query(ctx, http.MethodGet, scrollPath, nil, &page); err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
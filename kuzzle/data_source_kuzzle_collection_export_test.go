@@ -0,0 +1,123 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleCollectionExportRead(t *testing.T) {
+	tests := []struct {
+		name         string
+		wantErr      bool
+		wantExported int
+		wantFile     string
+		mocks        []Mock
+	}{
+		{
+			name:         "Scrolled documents",
+			wantErr:      false,
+			wantExported: 3,
+			wantFile:     "testdata/documents.ndjson",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/nyc-open-data/yellow-taxi/_search",
+					response: json.RawMessage(`{"result": {"total": 3, "scrollId": "scroll-1", "hits": [
+						{"_id": "1", "_source": {"name": "Ada"}},
+						{"_id": "2", "_source": {"name": "Grace"}}
+					]}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/_scroll/scroll-1",
+					response: json.RawMessage(`{"result": {"total": 3, "scrollId": "scroll-1", "hits": [
+						{"_id": "3", "_source": {"name": "Margaret"}}
+					]}}`),
+				},
+			},
+		},
+		{
+			name:         "Empty collection",
+			wantErr:      false,
+			wantExported: 0,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/nyc-open-data/yellow-taxi/_search",
+					response:   json.RawMessage(`{"result": {"total": 0, "hits": []}}`),
+				},
+			},
+		},
+		{
+			name:    "Expired scroll cursor",
+			wantErr: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/nyc-open-data/yellow-taxi/_search",
+					response:   json.RawMessage(`{"result": {"total": 3, "scrollId": "scroll-1", "hits": [{"_id": "1", "_source": {"name": "Ada"}}]}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 404,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/_scroll/scroll-1",
+					response:   json.RawMessage(`{"error": {"message": "Non-existing or expired scroll identifier"}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			path := filepath.Join(t.TempDir(), "export.ndjson")
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleCollectionExport().Schema, map[string]interface{}{
+				"index":      "nyc-open-data",
+				"collection": "yellow-taxi",
+				"path":       path,
+				"batch_size": 2,
+			})
+			diags := dataSourceKuzzleCollectionExportRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("dataSourceKuzzleCollectionExportRead() diags = %v, wantErr %v", diags, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := d.Get("exported").(int); got != tt.wantExported {
+				t.Errorf("dataSourceKuzzleCollectionExportRead() exported = %v, want %v", got, tt.wantExported)
+			}
+
+			got, _ := ioutil.ReadFile(path)
+			want := []byte{}
+			if tt.wantFile != "" {
+				want, _ = ioutil.ReadFile(tt.wantFile)
+			}
+			if string(got) != string(want) {
+				t.Errorf("dataSourceKuzzleCollectionExportRead() file = %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// searchHit is a document returned by a Kuzzle search
+type searchHit struct {
+	ID     string          `json:"_id"`
+	Source json.RawMessage `json:"_source"`
+}
+
+func dataSourceKuzzleCollectionSample() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns a sample of the documents of a Kuzzle collection",
+
+		ReadContext: dataSourceKuzzleCollectionSampleRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Collection name",
+			},
+			"size": { // Number of sampled documents
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				Description:  "Maximum number of documents to sample",
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+			"documents": { // Sampled documents
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON array of the sampled documents, each one with its _id and _source",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleCollectionSampleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	params := url.Values{}
+	params.Set("size", strconv.Itoa(d.Get("size").(int)))
+	path := "/" + url.PathEscape(index) + "/" + url.PathEscape(collection) + "/_search?" + params.Encode()
+
+	var search struct {
+		Hits []searchHit `json:"hits"`
+	}
+	if err := config.query(ctx, http.MethodPost, path, map[string]interface{}{}, &search); err != nil {
+		return diag.Errorf("Error sampling Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	hits := search.Hits
+	if hits == nil {
+		hits = []searchHit{}
+	}
+
+	documents, err := json.Marshal(hits)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(index + "/" + collection)
+	d.Set("documents", string(documents))
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleCollectionSampleRead(t *testing.T) {
+	tests := []struct {
+		name          string
+		size          int
+		wantErr       bool
+		wantDocuments string
+		mock          Mock
+	}{
+		{
+			name:          "Sampled documents",
+			size:          2,
+			wantErr:       false,
+			wantDocuments: `[{"_id":"1","_source":{"name":"Ada"}},{"_id":"2","_source":{"name":"Grace"}}]`,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "POST",
+				url:        "http://kuzzle:7512",
+				route:      "/nyc-open-data/yellow-taxi/_search",
+				response: json.RawMessage(`{"result": {"total": 42, "hits": [
+					{"_id": "1", "_source": {"name": "Ada"}},
+					{"_id": "2", "_source": {"name": "Grace"}}
+				]}}`),
+			},
+		},
+		{
+			name:          "Empty collection",
+			size:          10,
+			wantErr:       false,
+			wantDocuments: `[]`,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "POST",
+				url:        "http://kuzzle:7512",
+				route:      "/nyc-open-data/yellow-taxi/_search",
+				response:   json.RawMessage(`{"result": {"total": 0, "hits": []}}`),
+			},
+		},
+		{
+			name:    "Unknown collection",
+			size:    10,
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "POST",
+				url:        "http://kuzzle:7512",
+				route:      "/nyc-open-data/yellow-taxi/_search",
+				response:   json.RawMessage(`{"error": {"message": "Collection does not exist"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleCollectionSample().Schema, map[string]interface{}{
+				"index":      "nyc-open-data",
+				"collection": "yellow-taxi",
+				"size":       tt.size,
+			})
+			diags := dataSourceKuzzleCollectionSampleRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("dataSourceKuzzleCollectionSampleRead() diags = %v, wantErr %v", diags, tt.wantErr)
+				return
+			}
+			if got := d.Get("documents").(string); got != tt.wantDocuments {
+				t.Errorf("dataSourceKuzzleCollectionSampleRead() documents = %v, want %v", got, tt.wantDocuments)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleCollectionRead(t *testing.T) {
+	tests := []struct {
+		name              string
+		wantErr           bool
+		wantMappings      string
+		wantDynamicPolicy string
+		mock              Mock
+	}{
+		{
+			name:              "Success",
+			wantErr:           false,
+			wantMappings:      `{"_meta":{"owner":"iot"},"dynamic":"strict","properties":{"name":{"type":"keyword"}}}`,
+			wantDynamicPolicy: "strict",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mapping",
+				response:   json.RawMessage(`{"result": {"properties": {"name": {"type": "keyword"}}, "dynamic": "strict", "_meta": {"owner": "iot"}}}`),
+			},
+		},
+		{
+			name:              "Stored but not indexed fields",
+			wantErr:           false,
+			wantMappings:      `{"dynamic":"false","properties":{}}`,
+			wantDynamicPolicy: "false",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mapping",
+				response:   json.RawMessage(`{"result": {"dynamic": "false", "properties": {}}}`),
+			},
+		},
+		{
+			name:              "Boolean dynamic policy",
+			wantErr:           false,
+			wantMappings:      `{"dynamic":false,"properties":{}}`,
+			wantDynamicPolicy: "false",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mapping",
+				response:   json.RawMessage(`{"result": {"dynamic": false, "properties": {}}}`),
+			},
+		},
+		{
+			name:              "Default dynamic policy",
+			wantErr:           false,
+			wantMappings:      `{"properties":{}}`,
+			wantDynamicPolicy: "true",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mapping",
+				response:   json.RawMessage(`{"result": {"properties": {}}}`),
+			},
+		},
+		{
+			name:    "Unknown collection",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mapping",
+				response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_collection", "message": "Collection \"sensors\" does not exist."}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleCollection().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+			})
+			diags := dataSourceKuzzleCollectionRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("dataSourceKuzzleCollectionRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if got := d.Get("mappings").(string); got != tt.wantMappings {
+				t.Errorf("dataSourceKuzzleCollectionRead() mappings = %v, want %v", got, tt.wantMappings)
+			}
+			if got := d.Get("dynamic_policy").(string); got != tt.wantDynamicPolicy {
+				t.Errorf("dataSourceKuzzleCollectionRead() dynamic_policy = %v, want %v", got, tt.wantDynamicPolicy)
+			}
+		})
+	}
+}
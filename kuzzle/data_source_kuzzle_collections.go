@@ -0,0 +1,88 @@
+package kuzzle
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceKuzzleCollections() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the collections of a Kuzzle index",
+
+		ReadContext: dataSourceKuzzleCollectionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Index name",
+			},
+			"type": { // Type of the listed collections
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "all",
+				Description:  "Type of the listed collections: stored, realtime or all",
+				ValidateFunc: validation.StringInSlice([]string{"all", "stored", "realtime"}, false),
+			},
+			"names": { // Collection names
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Collection names, sorted alphabetically",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"name_set": { // Collection names, for for_each
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Collection names as a set, to be used with for_each",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"total": { // Number of collections
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of collections",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleCollectionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	kind := d.Get("type").(string)
+
+	var list struct {
+		Collections []struct {
+			Name string `json:"name"`
+		} `json:"collections"`
+	}
+	err := config.query(ctx, http.MethodGet, "/"+url.PathEscape(index)+"/_list?type="+kind, nil, &list)
+	if isNotFound(err) {
+		return diag.Errorf("Kuzzle index %q does not exist", index)
+	}
+	if err != nil {
+		return diag.Errorf("Error listing Kuzzle index %q collections: %s", index, err)
+	}
+
+	names := make([]string, 0, len(list.Collections))
+	for _, collection := range list.Collections {
+		names = append(names, collection.Name)
+	}
+	sort.Strings(names)
+
+	d.SetId(index + "/" + kind)
+	d.Set("total", len(names))
+	if err := d.Set("names", names); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("name_set", names); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
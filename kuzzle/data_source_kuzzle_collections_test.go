@@ -0,0 +1,90 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleCollectionsRead(t *testing.T) {
+	tests := []struct {
+		name       string
+		kind       string
+		statusCode int
+		response   string
+		wantErr    bool
+		wantNames  []string
+	}{
+		{
+			name:       "All collections",
+			kind:       "all",
+			statusCode: 200,
+			response:   `{"result": {"type": "all", "collections": [{"name": "sensors", "type": "stored"}, {"name": "alerts", "type": "realtime"}]}}`,
+			wantErr:    false,
+			wantNames:  []string{"alerts", "sensors"},
+		},
+		{
+			name:       "Stored collections",
+			kind:       "stored",
+			statusCode: 200,
+			response:   `{"result": {"type": "stored", "collections": [{"name": "sensors", "type": "stored"}]}}`,
+			wantErr:    false,
+			wantNames:  []string{"sensors"},
+		},
+		{
+			name:       "No collection",
+			kind:       "realtime",
+			statusCode: 200,
+			response:   `{"result": {"type": "realtime", "collections": []}}`,
+			wantErr:    false,
+			wantNames:  []string{},
+		},
+		{
+			name:       "Unknown index",
+			kind:       "all",
+			statusCode: 404,
+			response:   `{"error": {"id": "services.storage.unknown_index", "message": "Index \"iot\" does not exist."}}`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New("http://kuzzle:7512").
+				Get("/iot/_list").
+				MatchParam("type", tt.kind).
+				Reply(tt.statusCode).
+				JSON(json.RawMessage(tt.response))
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleCollections().Schema, map[string]interface{}{
+				"index": "iot",
+				"type":  tt.kind,
+			})
+			diags := dataSourceKuzzleCollectionsRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("dataSourceKuzzleCollectionsRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			gotNames := []string{}
+			for _, name := range d.Get("names").([]interface{}) {
+				gotNames = append(gotNames, name.(string))
+			}
+			if !reflect.DeepEqual(gotNames, tt.wantNames) {
+				t.Errorf("dataSourceKuzzleCollectionsRead() names = %v, want %v", gotNames, tt.wantNames)
+			}
+			if got := d.Get("name_set").(*schema.Set).Len(); got != len(tt.wantNames) {
+				t.Errorf("dataSourceKuzzleCollectionsRead() name_set length = %v, want %v", got, len(tt.wantNames))
+			}
+			if d.Get("total").(int) != len(tt.wantNames) {
+				t.Errorf("dataSourceKuzzleCollectionsRead() total = %v, want %v", d.Get("total"), len(tt.wantNames))
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceKuzzleDocument() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns an existing Kuzzle document, e.g. a configuration document written by an application, failing if it does not exist",
+
+		ReadContext: dataSourceKuzzleDocumentRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Collection name",
+			},
+			"document_id": { // Document identifier
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Document identifier",
+			},
+			"body": { // Document content
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON content of the document, without its Kuzzle metadata, with its keys sorted",
+			},
+			"version": { // Document version
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Version number of the document",
+			},
+			"kuzzle_info": { // Kuzzle metadata of the document
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Metadata Kuzzle stores along with the document",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"author": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Identifier of the user who created the document",
+						},
+						"created_at": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Creation timestamp, in milliseconds",
+						},
+						"updater": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Identifier of the user who last updated the document, empty if it was never updated",
+						},
+						"updated_at": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Last update timestamp, in milliseconds, 0 if it was never updated",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleDocumentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	documentID := d.Get("document_id").(string)
+
+	var document struct {
+		Version int                        `json:"_version"`
+		Source  map[string]json.RawMessage `json:"_source"`
+	}
+	err := config.query(ctx, http.MethodGet, documentPath(index, collection, documentID), nil, &document)
+	if isNotFound(err) {
+		return diag.Errorf("Kuzzle document %s/%s/%s does not exist", index, collection, documentID)
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle document %s/%s/%s: %s", index, collection, documentID, err)
+	}
+
+	var info kuzzleInfo
+	if raw, ok := document.Source["_kuzzle_info"]; ok {
+		if err := json.Unmarshal(raw, &info); err != nil {
+			return diag.Errorf("Error reading the metadata of Kuzzle document %s/%s/%s: %s", index, collection, documentID, err)
+		}
+		delete(document.Source, "_kuzzle_info")
+	}
+
+	raw, err := json.Marshal(document.Source)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	body, err := normalizeJSON(raw)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle document %s/%s/%s: %s", index, collection, documentID, err)
+	}
+
+	d.SetId(index + "/" + collection + "/" + documentID)
+	d.Set("body", body)
+	d.Set("version", document.Version)
+	err = d.Set("kuzzle_info", []interface{}{
+		map[string]interface{}{
+			"author":     info.Author,
+			"created_at": info.CreatedAt,
+			"updater":    info.Updater,
+			"updated_at": info.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
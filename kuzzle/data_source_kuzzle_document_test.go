@@ -0,0 +1,106 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleDocumentRead(t *testing.T) {
+	tests := []struct {
+		name           string
+		wantErr        bool
+		wantBody       string
+		wantVersion    int
+		wantKuzzleInfo []interface{}
+		mock           Mock
+	}{
+		{
+			name:        "Updated document",
+			wantErr:     false,
+			wantBody:    `{"features":{"beta":true},"theme":"dark"}`,
+			wantVersion: 3,
+			wantKuzzleInfo: []interface{}{
+				map[string]interface{}{
+					"author":     "ada",
+					"created_at": 1600000000000,
+					"updater":    "grace",
+					"updated_at": 1600000500000,
+				},
+			},
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/app/config/front",
+				response: json.RawMessage(`{"result": {"_id": "front", "_version": 3, "_source": {"theme": "dark", "features": {"beta": true},
+					"_kuzzle_info": {"author": "ada", "createdAt": 1600000000000, "updater": "grace", "updatedAt": 1600000500000}}}}`),
+			},
+		},
+		{
+			name:        "Never updated document",
+			wantErr:     false,
+			wantBody:    `{"theme":"light"}`,
+			wantVersion: 1,
+			wantKuzzleInfo: []interface{}{
+				map[string]interface{}{
+					"author":     "ada",
+					"created_at": 1600000000000,
+					"updater":    "",
+					"updated_at": 0,
+				},
+			},
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/app/config/front",
+				response: json.RawMessage(`{"result": {"_id": "front", "_version": 1, "_source": {"theme": "light",
+					"_kuzzle_info": {"author": "ada", "createdAt": 1600000000000, "updater": null, "updatedAt": null}}}}`),
+			},
+		},
+		{
+			name:    "Unknown document",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				url:        "http://kuzzle:7512",
+				route:      "/app/config/front",
+				response:   json.RawMessage(`{"error": {"id": "services.storage.not_found", "message": "Document \"front\" not found in \"app\":\"config\"."}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleDocument().Schema, map[string]interface{}{
+				"index":       "app",
+				"collection":  "config",
+				"document_id": "front",
+			})
+			diags := dataSourceKuzzleDocumentRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("dataSourceKuzzleDocumentRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := d.Get("body").(string); got != tt.wantBody {
+				t.Errorf("dataSourceKuzzleDocumentRead() body = %v, want %v", got, tt.wantBody)
+			}
+			if got := d.Get("version").(int); got != tt.wantVersion {
+				t.Errorf("dataSourceKuzzleDocumentRead() version = %v, want %v", got, tt.wantVersion)
+			}
+			if got := d.Get("kuzzle_info").([]interface{}); !reflect.DeepEqual(got, tt.wantKuzzleInfo) {
+				t.Errorf("dataSourceKuzzleDocumentRead() kuzzle_info = %v, want %v", got, tt.wantKuzzleInfo)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// documentVersionsNote explains why only the current version of a document is returned
+const documentVersionsNote = "Kuzzle does not keep the history of documents, only the current version is returned"
+
+// kuzzleInfo is the metadata Kuzzle stores along with each document
+type kuzzleInfo struct {
+	Author    string `json:"author"`
+	CreatedAt int    `json:"createdAt"`
+	Updater   string `json:"updater"`
+	UpdatedAt int    `json:"updatedAt"`
+}
+
+func dataSourceKuzzleDocumentVersions() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns the known versions of a Kuzzle document, with their metadata",
+
+		ReadContext: dataSourceKuzzleDocumentVersionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Collection name",
+			},
+			"document_id": { // Document identifier
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Document identifier",
+			},
+			"history_available": { // Whether prior versions can be read
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the prior versions of the document are available",
+			},
+			"note": { // Why the history is incomplete
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Explanation when the history of the document is not available",
+			},
+			"versions": { // Known versions of the document
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Known versions of the document, most recent first",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"version": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Version number",
+						},
+						"author": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Identifier of the user who created the document",
+						},
+						"created_at": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Creation timestamp, in milliseconds",
+						},
+						"updater": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Identifier of the user who last updated the document",
+						},
+						"updated_at": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Last update timestamp, in milliseconds",
+						},
+						"source": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "JSON content of the document, without its Kuzzle metadata",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleDocumentVersionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	documentID := d.Get("document_id").(string)
+
+	var document struct {
+		Version int                        `json:"_version"`
+		Source  map[string]json.RawMessage `json:"_source"`
+	}
+	path := "/" + url.PathEscape(index) + "/" + url.PathEscape(collection) + "/" + url.PathEscape(documentID)
+	if err := config.query(ctx, http.MethodGet, path, nil, &document); err != nil {
+		return diag.Errorf("Error reading Kuzzle document %s/%s/%s: %s", index, collection, documentID, err)
+	}
+
+	var info kuzzleInfo
+	if raw, ok := document.Source["_kuzzle_info"]; ok {
+		if err := json.Unmarshal(raw, &info); err != nil {
+			return diag.Errorf("Error reading the metadata of Kuzzle document %s/%s/%s: %s", index, collection, documentID, err)
+		}
+		delete(document.Source, "_kuzzle_info")
+	}
+
+	source, err := json.Marshal(document.Source)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(index + "/" + collection + "/" + documentID)
+	d.Set("history_available", false)
+	d.Set("note", documentVersionsNote)
+	err = d.Set("versions", []interface{}{
+		map[string]interface{}{
+			"version":    document.Version,
+			"author":     info.Author,
+			"created_at": info.CreatedAt,
+			"updater":    info.Updater,
+			"updated_at": info.UpdatedAt,
+			"source":     string(source),
+		},
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleDocumentVersionsRead(t *testing.T) {
+	tests := []struct {
+		name         string
+		wantErr      bool
+		wantVersions []interface{}
+		mock         Mock
+	}{
+		{
+			name:    "Updated document",
+			wantErr: false,
+			wantVersions: []interface{}{
+				map[string]interface{}{
+					"version":    3,
+					"author":     "ada",
+					"created_at": 1600000000000,
+					"updater":    "grace",
+					"updated_at": 1600000500000,
+					"source":     `{"name":"sensor-1"}`,
+				},
+			},
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/sensor-1",
+				response: json.RawMessage(`{"result": {"_id": "sensor-1", "_version": 3, "_source": {"name": "sensor-1",
+					"_kuzzle_info": {"author": "ada", "createdAt": 1600000000000, "updater": "grace", "updatedAt": 1600000500000}}}}`),
+			},
+		},
+		{
+			name:    "Not found",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/sensor-1",
+				response:   json.RawMessage(`{"error": {"message": "Document not found"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleDocumentVersions().Schema, map[string]interface{}{
+				"index":       "iot",
+				"collection":  "sensors",
+				"document_id": "sensor-1",
+			})
+			diags := dataSourceKuzzleDocumentVersionsRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("dataSourceKuzzleDocumentVersionsRead() diags = %v, wantErr %v", diags, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := d.Get("versions").([]interface{}); !reflect.DeepEqual(got, tt.wantVersions) {
+				t.Errorf("dataSourceKuzzleDocumentVersionsRead() versions = %v, want %v", got, tt.wantVersions)
+			}
+			if d.Get("history_available").(bool) || d.Get("note").(string) != documentVersionsNote {
+				t.Errorf("dataSourceKuzzleDocumentVersionsRead() did not report the missing history")
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package kuzzle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// documentsPageSize is the maximum number of documents fetched by each page of a search
+var documentsPageSize = 100
+
+func dataSourceKuzzleDocuments() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns the documents of a Kuzzle collection matching a search query, or the ones with the given ids",
+
+		ReadContext: dataSourceKuzzleDocumentsRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Collection name",
+			},
+			"query": { // Search query
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "JSON Elasticsearch query the documents must match, all the documents if neither it nor ids are set",
+				ValidateDiagFunc: validateJSON(jsonObject),
+			},
+			"ids": { // Identifiers of the documents
+				Type:          schema.TypeList,
+				Optional:      true,
+				MinItems:      1,
+				ConflictsWith: []string{"query"},
+				Description:   "Identifiers of the documents, fetched with a single document:mGet request instead of a search. The missing ones are left out",
+				Elem:          &schema.Schema{Type: schema.TypeString},
+			},
+			"max_results": { // Safety cap
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1000,
+				Description:  "Maximum number of documents returned by a search, the others being left out",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"total": { // Number of matching documents
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of documents matching the query, including the ones left out by max_results, or number of existing documents among ids",
+			},
+			"documents": { // Matching documents
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Matching documents, up to max_results",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Document unique identifier",
+						},
+						"source": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "JSON content of the document",
+						},
+					},
+				},
+			},
+			"bodies": { // Documents content by identifier
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "JSON content of the documents by identifier, without their Kuzzle metadata, e.g. for jsondecode(bodies[\"id\"])",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleDocumentsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	if ids := d.Get("ids").([]interface{}); len(ids) > 0 {
+		return dataSourceKuzzleDocumentsGet(ctx, d, config, index, collection, ids)
+	}
+
+	query := d.Get("query").(string)
+	maxResults := d.Get("max_results").(int)
+
+	body := map[string]interface{}{}
+	if query != "" {
+		body["query"] = json.RawMessage(query)
+	}
+
+	pageSize := documentsPageSize
+	if maxResults < pageSize {
+		pageSize = maxResults
+	}
+
+	documents := make([]interface{}, 0)
+	bodies := make(map[string]interface{})
+	total, err := scrollSearch(ctx, config, index, collection, body, pageSize, "1m", func(hit searchHit) (bool, error) {
+		documents = append(documents, map[string]interface{}{
+			"id":     hit.ID,
+			"source": string(hit.Source),
+		})
+
+		var source map[string]interface{}
+		if err := json.Unmarshal(hit.Source, &source); err != nil {
+			return false, err
+		}
+		documentBody, err := documentBody(source)
+		if err != nil {
+			return false, err
+		}
+		bodies[hit.ID] = documentBody
+
+		return len(documents) < maxResults, nil
+	})
+	if err != nil {
+		return diag.Diagnostics{errorDiagnostic("Error searching Kuzzle collection "+index+"/"+collection, err)}
+	}
+
+	sum := sha256.Sum256([]byte(query))
+	d.SetId(index + "/" + collection + "/" + hex.EncodeToString(sum[:8]))
+	d.Set("total", total)
+	if err := d.Set("documents", documents); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("bodies", bodies); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// dataSourceKuzzleDocumentsGet fetches the documents with the given ids in a single document:mGet request,
+// rather than one request per document
+func dataSourceKuzzleDocumentsGet(ctx context.Context, d *schema.ResourceData, config *Config, index string, collection string, raw []interface{}) diag.Diagnostics {
+	ids := make([]string, 0, len(raw))
+	for _, id := range raw {
+		ids = append(ids, id.(string))
+	}
+
+	sources, err := getDocuments(ctx, config, index, collection, ids, len(ids))
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle documents of collection %s/%s: %s", index, collection, err)
+	}
+
+	documents := make([]interface{}, 0, len(sources))
+	bodies := make(map[string]interface{}, len(sources))
+	for _, id := range ids {
+		source, ok := sources[id]
+		if !ok {
+			continue
+		}
+		if _, ok := bodies[id]; ok {
+			continue
+		}
+
+		raw, err := json.Marshal(source)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		body, err := documentBody(source)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		documents = append(documents, map[string]interface{}{
+			"id":     id,
+			"source": string(raw),
+		})
+		bodies[id] = body
+	}
+
+	d.SetId(index + "/" + collection + "/" + hashStrings(ids)[:16])
+	d.Set("total", len(documents))
+	if err := d.Set("documents", documents); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("bodies", bodies); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// documentBody returns the JSON content of a document source without its Kuzzle metadata, with its keys sorted
+func documentBody(source map[string]interface{}) (string, error) {
+	body := make(map[string]interface{}, len(source))
+	for field, value := range source {
+		if field != "_kuzzle_info" {
+			body[field] = value
+		}
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeJSON(raw)
+}
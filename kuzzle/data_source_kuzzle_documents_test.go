@@ -0,0 +1,180 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleDocumentsRead(t *testing.T) {
+	defaultPageSize := documentsPageSize
+	documentsPageSize = 2
+	defer func() { documentsPageSize = defaultPageSize }()
+
+	tests := []struct {
+		name        string
+		query       string
+		maxResults  int
+		wantBody    string
+		wantSize    string
+		pages       []json.RawMessage
+		wantIDs     []string
+		wantTotal   int
+		wantSummary string
+	}{
+		{
+			name:     "Several pages",
+			wantBody: `^\{\}$`,
+			wantSize: "2",
+			pages: []json.RawMessage{
+				json.RawMessage(`{"result": {"scrollId": "s1", "total": 3, "hits": [{"_id": "1", "_source": {"city": "Paris"}}, {"_id": "2", "_source": {"city": "Lyon"}}]}}`),
+				json.RawMessage(`{"result": {"scrollId": "s1", "total": 3, "hits": [{"_id": "3", "_source": {"city": "Nice"}}]}}`),
+			},
+			wantIDs:   []string{"1", "2", "3"},
+			wantTotal: 3,
+		},
+		{
+			name:       "Cap reached",
+			maxResults: 1,
+			wantBody:   `^\{\}$`,
+			wantSize:   "1",
+			pages: []json.RawMessage{
+				json.RawMessage(`{"result": {"scrollId": "s1", "total": 3, "hits": [{"_id": "1", "_source": {"city": "Paris"}}]}}`),
+			},
+			wantIDs:   []string{"1"},
+			wantTotal: 3,
+		},
+		{
+			name:     "Query",
+			query:    `{"match": {"city": "Paris"}}`,
+			wantBody: `^\{"query":\{"match":\{"city":"Paris"\}\}\}$`,
+			wantSize: "2",
+			pages: []json.RawMessage{
+				json.RawMessage(`{"result": {"scrollId": "s1", "total": 1, "hits": [{"_id": "1", "_source": {"city": "Paris"}}]}}`),
+			},
+			wantIDs:   []string{"1"},
+			wantTotal: 1,
+		},
+		{
+			name:     "Query error",
+			query:    `{"mtach": {}}`,
+			wantBody: `"mtach"`,
+			wantSize: "2",
+			pages: []json.RawMessage{
+				json.RawMessage(`{"status": 400, "error": {"id": "services.storage.unknown_query_keyword", "code": 123, "message": "Unknown query keyword \"mtach\".", "status": 400}}`),
+			},
+			wantSummary: `Unknown query keyword "mtach".`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			status := 200
+			if tt.wantSummary != "" {
+				status = 400
+			}
+			gock.
+				New("http://kuzzle:7512").
+				Post("/nyc-open-data/yellow-taxi/_search").
+				MatchParam("scroll", "^1m$").
+				MatchParam("size", "^"+tt.wantSize+"$").
+				BodyString(tt.wantBody).
+				Reply(status).
+				JSON(tt.pages[0])
+			for _, page := range tt.pages[1:] {
+				gock.
+					New("http://kuzzle:7512").
+					Get("/_scroll/s1").
+					MatchParam("scroll", "^1m$").
+					Reply(200).
+					JSON(page)
+			}
+
+			raw := map[string]interface{}{
+				"index":      "nyc-open-data",
+				"collection": "yellow-taxi",
+			}
+			if tt.query != "" {
+				raw["query"] = tt.query
+			}
+			if tt.maxResults > 0 {
+				raw["max_results"] = tt.maxResults
+			}
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleDocuments().Schema, raw)
+			diags := dataSourceKuzzleDocumentsRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != (tt.wantSummary != "") {
+				t.Errorf("dataSourceKuzzleDocumentsRead() diags = %v, wantSummary %q", diags, tt.wantSummary)
+				return
+			}
+			if !gock.IsDone() {
+				t.Errorf("dataSourceKuzzleDocumentsRead() did not fetch every page")
+			}
+			if tt.wantSummary != "" {
+				if diags[0].Summary != tt.wantSummary {
+					t.Errorf("dataSourceKuzzleDocumentsRead() summary = %q, want %q", diags[0].Summary, tt.wantSummary)
+				}
+				return
+			}
+
+			if got := d.Get("total").(int); got != tt.wantTotal {
+				t.Errorf("dataSourceKuzzleDocumentsRead() total = %v, want %v", got, tt.wantTotal)
+			}
+			documents := d.Get("documents").([]interface{})
+			if len(documents) != len(tt.wantIDs) {
+				t.Fatalf("dataSourceKuzzleDocumentsRead() returned %d documents, want %d", len(documents), len(tt.wantIDs))
+			}
+			for i, document := range documents {
+				fields := document.(map[string]interface{})
+				if fields["id"] != tt.wantIDs[i] {
+					t.Errorf("dataSourceKuzzleDocumentsRead() document %d id = %v, want %v", i, fields["id"], tt.wantIDs[i])
+				}
+				if !json.Valid([]byte(fields["source"].(string))) {
+					t.Errorf("dataSourceKuzzleDocumentsRead() document %d source = %v, want JSON", i, fields["source"])
+				}
+			}
+			if got := d.Get("bodies").(map[string]interface{}); len(got) != len(tt.wantIDs) {
+				t.Errorf("dataSourceKuzzleDocumentsRead() bodies = %v, want %d documents", got, len(tt.wantIDs))
+			}
+		})
+	}
+}
+
+// Documents fetched by id are read with a single mGet request, the missing ones being left out
+func Test_dataSourceKuzzleDocumentsReadIDs(t *testing.T) {
+	defer gock.Off()
+	gock.New("http://kuzzle:7512").
+		Post("/app/config/_mGet").
+		BodyString(`{"ids":["front","back","mobile"]}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {
+			"successes": [
+				{"_id": "front", "_source": {"theme": "dark", "_kuzzle_info": {"author": "ada"}}},
+				{"_id": "back", "_source": {"workers": 4}}
+			],
+			"errors": [{"document": {"_id": "mobile"}, "reason": "document not found", "status": 404}]
+		}}`))
+
+	d := schema.TestResourceDataRaw(t, dataSourceKuzzleDocuments().Schema, map[string]interface{}{
+		"index":      "app",
+		"collection": "config",
+		"ids":        []interface{}{"front", "back", "mobile"},
+	})
+	if diags := dataSourceKuzzleDocumentsRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Fatalf("dataSourceKuzzleDocumentsRead() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("dataSourceKuzzleDocumentsRead() pending mocks = %v", gock.Pending())
+	}
+
+	want := map[string]interface{}{"front": `{"theme":"dark"}`, "back": `{"workers":4}`}
+	if got := d.Get("bodies").(map[string]interface{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("dataSourceKuzzleDocumentsRead() bodies = %v, want %v", got, want)
+	}
+	if got := d.Get("total").(int); got != 2 {
+		t.Errorf("dataSourceKuzzleDocumentsRead() total = %v, want 2", got)
+	}
+}
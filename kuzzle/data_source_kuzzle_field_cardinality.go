@@ -0,0 +1,103 @@
+package kuzzle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceKuzzleFieldCardinality() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns an estimate of the number of distinct values of a field in a Kuzzle collection",
+
+		ReadContext: dataSourceKuzzleFieldCardinalityRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Collection name",
+			},
+			"field": { // Counted field
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Path of the field whose distinct values are counted, e.g. city or address.city.keyword",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"precision_threshold": { // Count below which the estimate is exact
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Count below which the estimate is expected to be close to exact, trading memory for accuracy, up to 40000",
+				ValidateFunc: validation.IntBetween(1, 40000),
+			},
+			"cardinality": { // Estimated number of distinct values
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Estimated number of distinct values of the field",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleFieldCardinalityRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	field := d.Get("field").(string)
+
+	cardinality, err := fieldCardinality(ctx, config, index, collection, field, d.Get("precision_threshold").(int))
+	if err != nil {
+		return diag.Errorf("Error counting the distinct values of %s in Kuzzle collection %s/%s: %s", field, index, collection, err)
+	}
+
+	d.SetId(index + "/" + collection + "/" + field)
+	d.Set("cardinality", cardinality)
+
+	return nil
+}
+
+// fieldCardinality runs a cardinality aggregation on a field, without fetching any document.
+// A precision threshold of 0 keeps the Elasticsearch default.
+func fieldCardinality(ctx context.Context, config *Config, index string, collection string, field string, precisionThreshold int) (int, error) {
+	aggregation := map[string]interface{}{"field": field}
+	if precisionThreshold > 0 {
+		aggregation["precision_threshold"] = precisionThreshold
+	}
+	body := map[string]interface{}{
+		"aggregations": map[string]interface{}{
+			"cardinality": map[string]interface{}{"cardinality": aggregation},
+		},
+	}
+
+	var search struct {
+		Aggregations struct {
+			Cardinality *struct {
+				Value int `json:"value"`
+			} `json:"cardinality"`
+		} `json:"aggregations"`
+	}
+	path := "/" + url.PathEscape(index) + "/" + url.PathEscape(collection) + "/_search?size=0"
+	err := config.query(ctx, http.MethodPost, path, body, &search)
+	if apiErr, ok := err.(*apiError); ok && apiErr.StatusCode == http.StatusBadRequest {
+		return 0, fmt.Errorf("field %s cannot be aggregated, text fields need a keyword sub-field such as %s.keyword: %s", field, field, apiErr.Message)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if search.Aggregations.Cardinality == nil {
+		return 0, fmt.Errorf("Kuzzle search result has no cardinality aggregation")
+	}
+
+	return search.Aggregations.Cardinality.Value, nil
+}
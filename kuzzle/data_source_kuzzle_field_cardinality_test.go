@@ -0,0 +1,89 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleFieldCardinalityRead(t *testing.T) {
+	tests := []struct {
+		name               string
+		precisionThreshold int
+		wantBody           string
+		statusCode         int
+		response           json.RawMessage
+		wantErr            string
+		wantCardinality    int
+	}{
+		{
+			name:            "Estimate",
+			wantBody:        `^\{"aggregations":\{"cardinality":\{"cardinality":\{"field":"city"\}\}\}\}$`,
+			statusCode:      200,
+			response:        json.RawMessage(`{"result": {"hits": [], "total": 1250, "aggregations": {"cardinality": {"value": 42}}}}`),
+			wantCardinality: 42,
+		},
+		{
+			name:               "Precision threshold",
+			precisionThreshold: 1000,
+			wantBody:           `"precision_threshold":1000`,
+			statusCode:         200,
+			response:           json.RawMessage(`{"result": {"hits": [], "total": 1250, "aggregations": {"cardinality": {"value": 37}}}}`),
+			wantCardinality:    37,
+		},
+		{
+			name:       "Text field",
+			wantBody:   `"field":"city"`,
+			statusCode: 400,
+			response:   json.RawMessage(`{"status": 400, "error": {"message": "Text fields are not optimised for operations that require per-document field data like aggregations and sorting"}}`),
+			wantErr:    "city cannot be aggregated",
+		},
+		{
+			name:       "Missing aggregation",
+			wantBody:   `"field":"city"`,
+			statusCode: 200,
+			response:   json.RawMessage(`{"result": {"hits": [], "total": 0}}`),
+			wantErr:    "no cardinality aggregation",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.
+				New("http://kuzzle:7512").
+				Post("/nyc-open-data/yellow-taxi/_search").
+				MatchParam("size", "^0$").
+				BodyString(tt.wantBody).
+				Reply(tt.statusCode).
+				JSON(tt.response)
+
+			raw := map[string]interface{}{
+				"index":      "nyc-open-data",
+				"collection": "yellow-taxi",
+				"field":      "city",
+			}
+			if tt.precisionThreshold > 0 {
+				raw["precision_threshold"] = tt.precisionThreshold
+			}
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleFieldCardinality().Schema, raw)
+			diags := dataSourceKuzzleFieldCardinalityRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != (tt.wantErr != "") {
+				t.Errorf("dataSourceKuzzleFieldCardinalityRead() diags = %v, wantErr %q", diags, tt.wantErr)
+				return
+			}
+			if tt.wantErr != "" {
+				if !strings.Contains(diags[0].Summary, tt.wantErr) {
+					t.Errorf("dataSourceKuzzleFieldCardinalityRead() diags = %v, want %q", diags, tt.wantErr)
+				}
+				return
+			}
+			if got := d.Get("cardinality").(int); got != tt.wantCardinality {
+				t.Errorf("dataSourceKuzzleFieldCardinalityRead() cardinality = %v, want %v", got, tt.wantCardinality)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package kuzzle
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceKuzzleIndexes() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the indexes of a Kuzzle server",
+
+		ReadContext: dataSourceKuzzleIndexesRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_regex": { // Filter on index names
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Regular expression the listed index names must match, all indexes being listed by default",
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"names": { // Index names
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Index names, sorted alphabetically",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"name_set": { // Index names, for for_each
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Index names as a set, to be used with for_each",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"total": { // Number of indexes, "count" being a reserved attribute name
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of listed indexes, named total as count is reserved by Terraform",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleIndexesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	var list struct {
+		Indexes []string `json:"indexes"`
+	}
+	if err := config.query(ctx, http.MethodGet, "/_list", nil, &list); err != nil {
+		return diag.Errorf("Error listing Kuzzle indexes: %s", err)
+	}
+
+	// The pattern is validated at plan time
+	pattern := regexp.MustCompile(d.Get("name_regex").(string))
+	names := []string{}
+	for _, name := range list.Indexes {
+		if pattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	d.SetId(hashStrings(names))
+	d.Set("total", len(names))
+	if err := d.Set("names", names); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("name_set", names); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// hashStrings returns a stable identifier for a sorted list of strings
+func hashStrings(values []string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(values, "\n"))))
+}
@@ -0,0 +1,109 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleIndexesRead(t *testing.T) {
+	tests := []struct {
+		name      string
+		nameRegex string
+		wantErr   bool
+		wantNames []string
+		wantID    string
+		mock      Mock
+	}{
+		{
+			name:      "Unordered indexes",
+			wantErr:   false,
+			wantNames: []string{"iot", "nyc-open-data", "tenant-a"},
+			wantID:    hashStrings([]string{"iot", "nyc-open-data", "tenant-a"}),
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/_list",
+				response:   json.RawMessage(`{"result": {"indexes": ["tenant-a", "iot", "nyc-open-data"]}}`),
+			},
+		},
+		{
+			name:      "Filtered indexes",
+			nameRegex: "^tenant-",
+			wantErr:   false,
+			wantNames: []string{"tenant-a", "tenant-b"},
+			wantID:    hashStrings([]string{"tenant-a", "tenant-b"}),
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/_list",
+				response:   json.RawMessage(`{"result": {"indexes": ["tenant-b", "iot", "tenant-a", "my-tenant-c"]}}`),
+			},
+		},
+		{
+			name:      "No index",
+			wantErr:   false,
+			wantNames: []string{},
+			wantID:    hashStrings([]string{}),
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/_list",
+				response:   json.RawMessage(`{"result": {"indexes": []}}`),
+			},
+		},
+		{
+			name:    "Forbidden",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 403,
+				url:        "http://kuzzle:7512",
+				route:      "/_list",
+				response:   json.RawMessage(`{"error": {"message": "Forbidden action"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleIndexes().Schema, map[string]interface{}{
+				"name_regex": tt.nameRegex,
+			})
+			diags := dataSourceKuzzleIndexesRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("dataSourceKuzzleIndexesRead() diags = %v, wantErr %v", diags, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			gotNames := []string{}
+			for _, name := range d.Get("names").([]interface{}) {
+				gotNames = append(gotNames, name.(string))
+			}
+			if !reflect.DeepEqual(gotNames, tt.wantNames) {
+				t.Errorf("dataSourceKuzzleIndexesRead() names = %v, want %v", gotNames, tt.wantNames)
+			}
+			if got := d.Get("name_set").(*schema.Set).Len(); got != len(tt.wantNames) {
+				t.Errorf("dataSourceKuzzleIndexesRead() name_set length = %v, want %v", got, len(tt.wantNames))
+			}
+			if d.Get("total").(int) != len(tt.wantNames) {
+				t.Errorf("dataSourceKuzzleIndexesRead() total = %v, want %v", d.Get("total"), len(tt.wantNames))
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("dataSourceKuzzleIndexesRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
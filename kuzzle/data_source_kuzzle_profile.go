@@ -0,0 +1,88 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceKuzzleProfile() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns an existing Kuzzle security profile, failing if it does not exist",
+
+		ReadContext: dataSourceKuzzleProfileRead,
+
+		Schema: map[string]*schema.Schema{
+			"profile_id": { // Profile unique identifier
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Profile unique identifier",
+			},
+			"policies": { // Roles granted by the profile
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON array of the profile policies, sorted by role id, with their keys sorted",
+			},
+			"rate_limit": { // Maximum number of requests per second
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum number of requests per second and per node for users with this profile, 0 if unlimited",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleProfileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	id := d.Get("profile_id").(string)
+
+	var profile struct {
+		Source struct {
+			Policies  []map[string]interface{} `json:"policies"`
+			RateLimit int                      `json:"rateLimit"`
+		} `json:"_source"`
+	}
+	err := config.query(ctx, http.MethodGet, "/profiles/"+url.PathEscape(id), nil, &profile)
+	if isNotFound(err) {
+		return diag.Errorf("Kuzzle profile %q does not exist", id)
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle profile %q: %s", id, err)
+	}
+
+	policies, err := normalizePolicies(profile.Source.Policies)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle profile %q: %s", id, err)
+	}
+
+	d.SetId(id)
+	d.Set("policies", policies)
+	d.Set("rate_limit", profile.Source.RateLimit)
+
+	return nil
+}
+
+// normalizePolicies returns the JSON of profile policies sorted by role id, so that it only changes with the policies.
+// Policies are kept as decoded, so that the attributes unknown to the provider are not lost.
+func normalizePolicies(policies []map[string]interface{}) (string, error) {
+	if policies == nil {
+		policies = []map[string]interface{}{}
+	}
+
+	sort.SliceStable(policies, func(i, j int) bool {
+		return fmt.Sprint(policies[i]["roleId"]) < fmt.Sprint(policies[j]["roleId"])
+	})
+
+	normalized, err := json.Marshal(policies)
+	if err != nil {
+		return "", err
+	}
+
+	return string(normalized), nil
+}
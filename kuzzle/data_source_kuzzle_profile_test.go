@@ -0,0 +1,83 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleProfileRead(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		response      json.RawMessage
+		wantErr       string
+		wantPolicies  string
+		wantRateLimit int
+	}{
+		{
+			name:       "Profile",
+			statusCode: 200,
+			response: json.RawMessage(`{"result": {"_id": "editor", "_source": {"rateLimit": 50, "policies": [
+				{"roleId": "writer", "restrictedTo": [{"collections": ["sensors"], "index": "iot"}]},
+				{"roleId": "default"}
+			]}}}`),
+			wantPolicies:  `[{"roleId":"default"},{"restrictedTo":[{"collections":["sensors"],"index":"iot"}],"roleId":"writer"}]`,
+			wantRateLimit: 50,
+		},
+		{
+			name:         "No policies",
+			statusCode:   200,
+			response:     json.RawMessage(`{"result": {"_id": "editor", "_source": {}}}`),
+			wantPolicies: `[]`,
+		},
+		{
+			name:       "Not found",
+			statusCode: 404,
+			response:   json.RawMessage(`{"status": 404, "error": {"id": "security.profile.not_found", "message": "Profile \"editor\" not found.", "status": 404}}`),
+			wantErr:    `Kuzzle profile "editor" does not exist`,
+		},
+		{
+			name:       "Forbidden",
+			statusCode: 403,
+			response:   json.RawMessage(`{"status": 403, "error": {"id": "security.rights.forbidden", "message": "Forbidden action", "status": 403}}`),
+			wantErr:    `Error reading Kuzzle profile "editor"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New("http://kuzzle:7512").
+				Get("/profiles/editor").
+				Reply(tt.statusCode).
+				JSON(tt.response)
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleProfile().Schema, map[string]interface{}{"profile_id": "editor"})
+			diags := dataSourceKuzzleProfileRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != (tt.wantErr != "") {
+				t.Errorf("dataSourceKuzzleProfileRead() diags = %v, wantErr %q", diags, tt.wantErr)
+				return
+			}
+			if tt.wantErr != "" {
+				if !strings.Contains(diags[0].Summary, tt.wantErr) {
+					t.Errorf("dataSourceKuzzleProfileRead() diags = %v, want %q", diags, tt.wantErr)
+				}
+				return
+			}
+
+			if got := d.Get("policies").(string); got != tt.wantPolicies {
+				t.Errorf("dataSourceKuzzleProfileRead() policies = %v, want %v", got, tt.wantPolicies)
+			}
+			if got := d.Get("rate_limit").(int); got != tt.wantRateLimit {
+				t.Errorf("dataSourceKuzzleProfileRead() rate_limit = %v, want %v", got, tt.wantRateLimit)
+			}
+			if d.Id() != "editor" {
+				t.Errorf("dataSourceKuzzleProfileRead() id = %v, want editor", d.Id())
+			}
+		})
+	}
+}
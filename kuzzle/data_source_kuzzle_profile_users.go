@@ -0,0 +1,87 @@
+package kuzzle
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// usersPageSize is the number of users fetched by each security:searchUsers request
+var usersPageSize = 100
+
+func dataSourceKuzzleProfileUsers() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the Kuzzle users holding a given profile",
+
+		ReadContext: dataSourceKuzzleProfileUsersRead,
+
+		Schema: map[string]*schema.Schema{
+			"profile_id": { // Profile identifier
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Profile identifier",
+			},
+			"user_ids": { // Identifiers of the users holding the profile
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Identifiers of the users holding the profile, sorted alphabetically",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleProfileUsersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	profileID := d.Get("profile_id").(string)
+
+	userIDs, err := searchProfileUsers(ctx, config, profileID)
+	if err != nil {
+		return diag.Errorf("Error searching the users of Kuzzle profile %q: %s", profileID, err)
+	}
+	sort.Strings(userIDs)
+
+	d.SetId(profileID)
+	if err := d.Set("user_ids", userIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// searchProfileUsers returns the identifiers of all the users holding the profile, one page at a time
+func searchProfileUsers(ctx context.Context, config *Config, profileID string) ([]string, error) {
+	body := map[string]interface{}{
+		"query": map[string]interface{}{
+			"terms": map[string]interface{}{"profileIds": []string{profileID}},
+		},
+	}
+
+	userIDs := []string{}
+	for {
+		params := url.Values{}
+		params.Set("from", strconv.Itoa(len(userIDs)))
+		params.Set("size", strconv.Itoa(usersPageSize))
+
+		var page struct {
+			Hits  []searchHit `json:"hits"`
+			Total int         `json:"total"`
+		}
+		if err := config.query(ctx, http.MethodPost, "/users/_search?"+params.Encode(), body, &page); err != nil {
+			return nil, err
+		}
+
+		for _, hit := range page.Hits {
+			userIDs = append(userIDs, hit.ID)
+		}
+
+		if len(page.Hits) == 0 || len(userIDs) >= page.Total {
+			return userIDs, nil
+		}
+	}
+}
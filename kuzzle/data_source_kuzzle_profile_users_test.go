@@ -0,0 +1,96 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleProfileUsersRead(t *testing.T) {
+	type page struct {
+		from     string
+		response json.RawMessage
+	}
+
+	tests := []struct {
+		name        string
+		statusCode  int
+		pages       []page
+		wantErr     bool
+		wantUserIDs []string
+	}{
+		{
+			name:       "Several pages",
+			statusCode: 200,
+			pages: []page{
+				{from: "0", response: json.RawMessage(`{"result": {"total": 3, "hits": [{"_id": "ada"}, {"_id": "grace"}]}}`)},
+				{from: "2", response: json.RawMessage(`{"result": {"total": 3, "hits": [{"_id": "alan"}]}}`)},
+			},
+			wantErr:     false,
+			wantUserIDs: []string{"ada", "alan", "grace"},
+		},
+		{
+			name:       "No user",
+			statusCode: 200,
+			pages: []page{
+				{from: "0", response: json.RawMessage(`{"result": {"total": 0, "hits": []}}`)},
+			},
+			wantErr:     false,
+			wantUserIDs: []string{},
+		},
+		{
+			name:       "Forbidden",
+			statusCode: 403,
+			pages: []page{
+				{from: "0", response: json.RawMessage(`{"error": {"message": "Forbidden action"}}`)},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(size int) { usersPageSize = size }(usersPageSize)
+			usersPageSize = 2
+
+			defer gock.Off()
+			for _, p := range tt.pages {
+				gock.
+					New("http://kuzzle:7512").
+					Post("/users/_search").
+					MatchParam("from", p.from).
+					MatchParam("size", "2").
+					BodyString(`"profileIds":\["editor"\]`).
+					Reply(tt.statusCode).
+					JSON(p.response)
+			}
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleProfileUsers().Schema, map[string]interface{}{"profile_id": "editor"})
+			diags := dataSourceKuzzleProfileUsersRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("dataSourceKuzzleProfileUsersRead() diags = %v, wantErr %v", diags, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, id := range d.Get("user_ids").([]interface{}) {
+				got = append(got, id.(string))
+			}
+			if got == nil {
+				got = []string{}
+			}
+			if !reflect.DeepEqual(got, tt.wantUserIDs) {
+				t.Errorf("dataSourceKuzzleProfileUsersRead() user_ids = %v, want %v", got, tt.wantUserIDs)
+			}
+			if !gock.IsDone() {
+				t.Errorf("dataSourceKuzzleProfileUsersRead() did not fetch every page")
+			}
+		})
+	}
+}
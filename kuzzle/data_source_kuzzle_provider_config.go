@@ -0,0 +1,91 @@
+package kuzzle
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Ways the provider authenticates its requests
+const (
+	authMethodAnonymous = "anonymous" // No token is sent
+	authMethodAPIKey    = "api_key"   // The API key is sent as is
+	authMethodJWT       = "jwt"       // The JWT given in the configuration is sent as is
+	authMethodLogin     = "login"     // A token is obtained by logging in with a strategy
+)
+
+func dataSourceKuzzleProviderConfig() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns the resolved settings of the provider, without any secret, to check how it was configured",
+
+		ReadContext: dataSourceKuzzleProviderConfigRead,
+
+		Schema: map[string]*schema.Schema{
+			"endpoint": { // Kuzzle endpoint URL
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Kuzzle endpoint URL, without any password it may hold",
+			},
+			"protocol": { // Protocol used to reach Kuzzle
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Protocol of the endpoint: http, https, ws or wss. With ws and wss, only the connection, login and API key checks go through the WebSocket API, other requests being sent over HTTP",
+			},
+			"auth_method": { // How requests are authenticated
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How requests are authenticated: api_key, jwt, login or anonymous",
+			},
+			"auth_strategy": { // Strategy used to log in
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Authentication strategy used to log in, empty unless auth_method is login",
+			},
+			"timeout": { // Request timeout
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Time limit, in seconds, of each request sent to Kuzzle, 0 for no limit",
+			},
+			"max_retries": { // Retries after transient failures
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of times idempotent requests are retried after a transient failure",
+			},
+			"rate_limit_max_wait": { // Budget for rate limited requests
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum total time, in seconds, spent waiting before retrying rate limited requests",
+			},
+			"run_id": { // Terraform run id
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Terraform run id sent with every request",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleProviderConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	endpoint, protocol := config.Endpoint, ""
+	if URL, err := url.Parse(config.Endpoint); err == nil {
+		endpoint, protocol = URL.Redacted(), URL.Scheme
+	}
+
+	method, strategy := config.authMethod()
+
+	d.SetId(endpoint)
+	d.Set("endpoint", endpoint)
+	d.Set("protocol", protocol)
+	d.Set("auth_method", method)
+	d.Set("auth_strategy", strategy)
+	d.Set("timeout", int(config.httpClient().Timeout.Seconds()))
+	d.Set("max_retries", config.MaxRetries)
+	d.Set("rate_limit_max_wait", int(config.RateLimitMaxWait.Seconds()))
+	d.Set("run_id", config.RunID)
+
+	return nil
+}
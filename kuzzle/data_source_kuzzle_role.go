@@ -0,0 +1,60 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceKuzzleRole() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns an existing Kuzzle security role, failing if it does not exist",
+
+		ReadContext: dataSourceKuzzleRoleRead,
+
+		Schema: map[string]*schema.Schema{
+			"role_id": { // Role unique identifier
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Role unique identifier",
+			},
+			"controllers": { // API actions granted by the role
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON object of the API actions the role grants, by controller, with its keys sorted",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	id := d.Get("role_id").(string)
+
+	var role struct {
+		Source struct {
+			Controllers json.RawMessage `json:"controllers"`
+		} `json:"_source"`
+	}
+	err := config.query(ctx, http.MethodGet, "/roles/"+url.PathEscape(id), nil, &role)
+	if isNotFound(err) {
+		return diag.Errorf("Kuzzle role %q does not exist", id)
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle role %q: %s", id, err)
+	}
+
+	controllers, err := normalizeJSON(role.Source.Controllers)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle role %q: %s", id, err)
+	}
+
+	d.SetId(id)
+	d.Set("controllers", controllers)
+
+	return nil
+}
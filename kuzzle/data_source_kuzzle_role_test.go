@@ -0,0 +1,69 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleRoleRead(t *testing.T) {
+	tests := []struct {
+		name            string
+		statusCode      int
+		response        json.RawMessage
+		wantErr         string
+		wantControllers string
+	}{
+		{
+			name:       "Role",
+			statusCode: 200,
+			response: json.RawMessage(`{"result": {"_id": "writer", "_source": {"controllers": {
+				"document": {"actions": {"update": true, "create": true}},
+				"auth": {"actions": {"login": true}}
+			}}}}`),
+			wantControllers: `{"auth":{"actions":{"login":true}},"document":{"actions":{"create":true,"update":true}}}`,
+		},
+		{
+			name:            "No controllers",
+			statusCode:      200,
+			response:        json.RawMessage(`{"result": {"_id": "writer", "_source": {}}}`),
+			wantControllers: `{}`,
+		},
+		{
+			name:       "Not found",
+			statusCode: 404,
+			response:   json.RawMessage(`{"status": 404, "error": {"id": "security.role.not_found", "message": "Role \"writer\" not found.", "status": 404}}`),
+			wantErr:    `Kuzzle role "writer" does not exist`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New("http://kuzzle:7512").
+				Get("/roles/writer").
+				Reply(tt.statusCode).
+				JSON(tt.response)
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleRole().Schema, map[string]interface{}{"role_id": "writer"})
+			diags := dataSourceKuzzleRoleRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != (tt.wantErr != "") {
+				t.Errorf("dataSourceKuzzleRoleRead() diags = %v, wantErr %q", diags, tt.wantErr)
+				return
+			}
+			if tt.wantErr != "" {
+				if !strings.Contains(diags[0].Summary, tt.wantErr) {
+					t.Errorf("dataSourceKuzzleRoleRead() diags = %v, want %q", diags, tt.wantErr)
+				}
+				return
+			}
+
+			if got := d.Get("controllers").(string); got != tt.wantControllers {
+				t.Errorf("dataSourceKuzzleRoleRead() controllers = %v, want %v", got, tt.wantControllers)
+			}
+		})
+	}
+}
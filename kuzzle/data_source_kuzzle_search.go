@@ -0,0 +1,134 @@
+package kuzzle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceKuzzleSearch() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns a page of the documents of a Kuzzle collection matching a search query, with a single document:search request",
+
+		ReadContext: dataSourceKuzzleSearchRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Collection name",
+			},
+			"query": { // Search query
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "JSON Elasticsearch query the documents must match, e.g. {\"match\": {\"name\": \"acme\"}}, all the documents if not set",
+				ValidateDiagFunc: validateJSON(jsonObject),
+			},
+			"sort": { // Sort of the documents
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "JSON Elasticsearch sort of the documents, e.g. [{\"createdAt\": \"desc\"}]",
+				ValidateDiagFunc: validateJSON(nil),
+			},
+			"size": { // Page size
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				Description:  "Maximum number of documents returned",
+				ValidateFunc: validation.IntBetween(1, 10000),
+			},
+			"from": { // Page offset
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Number of matching documents skipped",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"total": { // Number of matching documents
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of documents matching the query, including the ones out of the page",
+			},
+			"documents": { // Matching documents
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Matching documents, in the search order",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Document unique identifier",
+						},
+						"source": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "JSON content of the document",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleSearchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	body := map[string]interface{}{}
+	if query := d.Get("query").(string); query != "" {
+		body["query"] = json.RawMessage(query)
+	}
+	if sort := d.Get("sort").(string); sort != "" {
+		body["sort"] = json.RawMessage(sort)
+	}
+
+	params := url.Values{}
+	params.Set("from", strconv.Itoa(d.Get("from").(int)))
+	params.Set("size", strconv.Itoa(d.Get("size").(int)))
+	path := collectionPath(index, collection) + "/_search?" + params.Encode()
+
+	var search struct {
+		Total int         `json:"total"`
+		Hits  []searchHit `json:"hits"`
+	}
+	if err := config.query(ctx, http.MethodPost, path, body, &search); err != nil {
+		return diag.Diagnostics{errorDiagnostic("Error searching Kuzzle collection "+index+"/"+collection, err)}
+	}
+
+	documents := make([]interface{}, 0, len(search.Hits))
+	for _, hit := range search.Hits {
+		documents = append(documents, map[string]interface{}{
+			"id":     hit.ID,
+			"source": string(hit.Source),
+		})
+	}
+
+	request, err := json.Marshal(body)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	sum := sha256.Sum256([]byte(string(request) + "?" + params.Encode()))
+	d.SetId(index + "/" + collection + "/" + hex.EncodeToString(sum[:8]))
+	d.Set("total", search.Total)
+	if err := d.Set("documents", documents); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleSearchRead(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         map[string]interface{}
+		wantBody    string
+		wantFrom    string
+		wantSize    string
+		statusCode  int
+		response    string
+		wantIDs     []string
+		wantTotal   int
+		wantSummary string
+	}{
+		{
+			name:       "All documents",
+			raw:        map[string]interface{}{},
+			wantBody:   `{}`,
+			wantFrom:   "0",
+			wantSize:   "10",
+			statusCode: 200,
+			response:   `{"result": {"total": 2, "hits": [{"_id": "acme", "_source": {"name": "Acme"}}, {"_id": "globex", "_source": {"name": "Globex"}}]}}`,
+			wantIDs:    []string{"acme", "globex"},
+			wantTotal:  2,
+		},
+		{
+			name: "Query, sort and page",
+			raw: map[string]interface{}{
+				"query": `{"match": {"name": "acme"}}`,
+				"sort":  `[{"createdAt": "desc"}]`,
+				"size":  1,
+				"from":  2,
+			},
+			wantBody:   `{"query":{"match":{"name":"acme"}},"sort":[{"createdAt":"desc"}]}`,
+			wantFrom:   "2",
+			wantSize:   "1",
+			statusCode: 200,
+			response:   `{"result": {"total": 5, "hits": [{"_id": "acme-3", "_source": {"name": "Acme"}}]}}`,
+			wantIDs:    []string{"acme-3"},
+			wantTotal:  5,
+		},
+		{
+			name:        "Query error",
+			raw:         map[string]interface{}{"query": `{"mtach": {}}`},
+			wantBody:    `{"query":{"mtach":{}}}`,
+			wantFrom:    "0",
+			wantSize:    "10",
+			statusCode:  400,
+			response:    `{"status": 400, "error": {"id": "services.storage.unknown_query_keyword", "message": "Unknown query keyword \"mtach\".", "status": 400}}`,
+			wantSummary: `Unknown query keyword "mtach".`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New("http://kuzzle:7512").
+				Post("/tenants/accounts/_search").
+				MatchParam("from", "^"+tt.wantFrom+"$").
+				MatchParam("size", "^"+tt.wantSize+"$").
+				BodyString(tt.wantBody).
+				Reply(tt.statusCode).
+				JSON(json.RawMessage(tt.response))
+
+			raw := map[string]interface{}{
+				"index":      "tenants",
+				"collection": "accounts",
+			}
+			for key, value := range tt.raw {
+				raw[key] = value
+			}
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleSearch().Schema, raw)
+			diags := dataSourceKuzzleSearchRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != (tt.wantSummary != "") {
+				t.Fatalf("dataSourceKuzzleSearchRead() diags = %v, wantSummary %q", diags, tt.wantSummary)
+			}
+			if !gock.IsDone() {
+				t.Errorf("dataSourceKuzzleSearchRead() pending mocks = %v", gock.Pending())
+			}
+			if tt.wantSummary != "" {
+				if diags[0].Summary != tt.wantSummary {
+					t.Errorf("dataSourceKuzzleSearchRead() summary = %q, want %q", diags[0].Summary, tt.wantSummary)
+				}
+				return
+			}
+
+			if got := d.Get("total").(int); got != tt.wantTotal {
+				t.Errorf("dataSourceKuzzleSearchRead() total = %v, want %v", got, tt.wantTotal)
+			}
+			documents := d.Get("documents").([]interface{})
+			if len(documents) != len(tt.wantIDs) {
+				t.Fatalf("dataSourceKuzzleSearchRead() returned %d documents, want %d", len(documents), len(tt.wantIDs))
+			}
+			for i, document := range documents {
+				if id := document.(map[string]interface{})["id"]; id != tt.wantIDs[i] {
+					t.Errorf("dataSourceKuzzleSearchRead() document %d id = %v, want %v", i, id, tt.wantIDs[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package kuzzle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// userRight is a single right of a Kuzzle user, as returned by auth:getMyRights
+type userRight struct {
+	Controller string `json:"controller"`
+	Action     string `json:"action"`
+	Index      string `json:"index"`
+	Collection string `json:"collection"`
+	Value      string `json:"value"`
+}
+
+func dataSourceKuzzleSecurityStatus() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reports whether a Kuzzle cluster is still unsecured or has an administrator",
+
+		ReadContext: dataSourceKuzzleSecurityStatusRead,
+
+		Schema: map[string]*schema.Schema{
+			"admin_exists": { // Whether an administrator account exists
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether an administrator account has been created",
+			},
+			"security_enabled": { // Whether anonymous users are restricted
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether anonymous users are denied at least some API actions",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleSecurityStatusRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	var admin struct {
+		Exists bool `json:"exists"`
+	}
+	if err := config.query(ctx, http.MethodGet, "/_adminExists", nil, &admin); err != nil {
+		return diag.Errorf("Error checking if a Kuzzle administrator exists: %s", err)
+	}
+
+	// Rights are probed anonymously, whatever the provider credentials are
+	anonymous := *config
+	anonymous.Token = ""
+
+	var rights struct {
+		Hits []userRight `json:"hits"`
+	}
+	securityEnabled := false
+	err := anonymous.query(ctx, http.MethodGet, "/users/_me/_rights", nil, &rights)
+	var apiErr *apiError
+	switch {
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		// Anonymous users that may not even read their own rights are restricted
+		securityEnabled = true
+	case err != nil:
+		return diag.Errorf("Error reading Kuzzle anonymous user rights: %s", err)
+	default:
+		securityEnabled = !hasUnrestrictedRight(rights.Hits)
+	}
+
+	d.SetId(config.Endpoint)
+	d.Set("admin_exists", admin.Exists)
+	d.Set("security_enabled", securityEnabled)
+
+	return nil
+}
+
+// hasUnrestrictedRight tells whether the rights allow any action on any index and collection
+func hasUnrestrictedRight(rights []userRight) bool {
+	for _, right := range rights {
+		if right.Controller == "*" && right.Action == "*" &&
+			right.Index == "*" && right.Collection == "*" &&
+			right.Value == "allowed" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,144 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleSecurityStatusRead(t *testing.T) {
+	tests := []struct {
+		name                string
+		wantErr             bool
+		wantAdminExists     bool
+		wantSecurityEnabled bool
+		mocks               []Mock
+	}{
+		{
+			name:                "Fresh cluster",
+			wantErr:             false,
+			wantAdminExists:     false,
+			wantSecurityEnabled: false,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					url:        "http://kuzzle:7512",
+					route:      "/_adminExists",
+					response:   json.RawMessage(`{"result": {"exists": false}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					url:        "http://kuzzle:7512",
+					route:      "/users/_me/_rights",
+					response:   json.RawMessage(`{"result": {"hits": [{"controller": "*", "action": "*", "index": "*", "collection": "*", "value": "allowed"}]}}`),
+				},
+			},
+		},
+		{
+			name:                "Secured cluster",
+			wantErr:             false,
+			wantAdminExists:     true,
+			wantSecurityEnabled: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					url:        "http://kuzzle:7512",
+					route:      "/_adminExists",
+					response:   json.RawMessage(`{"result": {"exists": true}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					url:        "http://kuzzle:7512",
+					route:      "/users/_me/_rights",
+					response:   json.RawMessage(`{"result": {"hits": [{"controller": "auth", "action": "login", "index": "*", "collection": "*", "value": "allowed"}]}}`),
+				},
+			},
+		},
+		{
+			name:                "Rights refused to anonymous users",
+			wantErr:             false,
+			wantAdminExists:     true,
+			wantSecurityEnabled: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					url:        "http://kuzzle:7512",
+					route:      "/_adminExists",
+					response:   json.RawMessage(`{"result": {"exists": true}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 401,
+					url:        "http://kuzzle:7512",
+					route:      "/users/_me/_rights",
+					response:   json.RawMessage(`{"error": {"id": "security.rights.unauthorized", "message": "Unauthorized action [auth/getMyRights] for anonymous user"}}`),
+				},
+			},
+		},
+		{
+			name:                "Rights forbidden to anonymous users",
+			wantErr:             false,
+			wantAdminExists:     true,
+			wantSecurityEnabled: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					url:        "http://kuzzle:7512",
+					route:      "/_adminExists",
+					response:   json.RawMessage(`{"result": {"exists": true}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 403,
+					url:        "http://kuzzle:7512",
+					route:      "/users/_me/_rights",
+					response:   json.RawMessage(`{"error": {"id": "security.rights.forbidden", "message": "Forbidden action [auth/getMyRights] for anonymous user"}}`),
+				},
+			},
+		},
+		{
+			name:    "Server error",
+			wantErr: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 500,
+					url:        "http://kuzzle:7512",
+					route:      "/_adminExists",
+					response:   json.RawMessage(`{"error": {"message": "Internal error"}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleSecurityStatus().Schema, map[string]interface{}{})
+			diags := dataSourceKuzzleSecurityStatusRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512", Token: "myToken"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("dataSourceKuzzleSecurityStatusRead() diags = %v, wantErr %v", diags, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := d.Get("admin_exists").(bool); got != tt.wantAdminExists {
+				t.Errorf("dataSourceKuzzleSecurityStatusRead() admin_exists = %v, want %v", got, tt.wantAdminExists)
+			}
+			if got := d.Get("security_enabled").(bool); got != tt.wantSecurityEnabled {
+				t.Errorf("dataSourceKuzzleSecurityStatusRead() security_enabled = %v, want %v", got, tt.wantSecurityEnabled)
+			}
+		})
+	}
+}
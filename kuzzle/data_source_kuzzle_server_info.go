@@ -0,0 +1,78 @@
+package kuzzle
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceKuzzleServerInfo() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns the version, node name and API routes of the Kuzzle server, as reported by server:info",
+
+		ReadContext: dataSourceKuzzleServerInfoRead,
+
+		Schema: map[string]*schema.Schema{
+			"version": { // Kuzzle version
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Version of the Kuzzle server",
+			},
+			"node_name": { // Name of the node which answered
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the cluster node which answered, empty if Kuzzle does not report it",
+			},
+			"api_routes": { // Enabled API actions
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Enabled API actions, plugin ones included, as sorted controller:action names",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleServerInfoRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	var info struct {
+		ServerInfo struct {
+			Kuzzle struct {
+				Version string `json:"version"`
+				NodeID  string `json:"nodeId"`
+				API     struct {
+					Routes map[string]map[string]interface{} `json:"routes"`
+				} `json:"api"`
+			} `json:"kuzzle"`
+		} `json:"serverInfo"`
+	}
+	if err := config.query(ctx, http.MethodGet, "/_serverInfo", nil, &info); err != nil {
+		return diag.Errorf("Error reading Kuzzle server info: %s", err)
+	}
+
+	kuzzle := info.ServerInfo.Kuzzle
+	if kuzzle.Version == "" {
+		return diag.Errorf("Error reading Kuzzle server info: Kuzzle server version is unknown")
+	}
+
+	routes := make([]string, 0)
+	for controller, actions := range kuzzle.API.Routes {
+		for action := range actions {
+			routes = append(routes, controller+":"+action)
+		}
+	}
+	sort.Strings(routes)
+
+	d.SetId(config.Endpoint)
+	d.Set("version", kuzzle.Version)
+	d.Set("node_name", kuzzle.NodeID)
+	if err := d.Set("api_routes", routes); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleServerInfoRead(t *testing.T) {
+	tests := []struct {
+		name         string
+		wantErr      bool
+		wantVersion  string
+		wantNodeName string
+		wantRoutes   []interface{}
+		mock         Mock
+	}{
+		{
+			name:         "Success",
+			wantErr:      false,
+			wantVersion:  "2.14.2",
+			wantNodeName: "knode-nasty-author-4242",
+			wantRoutes:   []interface{}{"document:create", "document:get", "my-plugin/greeting:hello", "server:info"},
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/_serverInfo",
+				response: json.RawMessage(`{"result": {"serverInfo": {"kuzzle": {
+					"version": "2.14.2",
+					"nodeId": "knode-nasty-author-4242",
+					"api": {"routes": {
+						"server": {"info": {"http": [{"verb": "GET", "url": "/_serverInfo"}]}},
+						"document": {"get": {}, "create": {}},
+						"my-plugin/greeting": {"hello": {}}
+					}}
+				}}}}`),
+			},
+		},
+		{
+			name:         "Without node name",
+			wantErr:      false,
+			wantVersion:  "2.0.0",
+			wantNodeName: "",
+			wantRoutes:   []interface{}{},
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/_serverInfo",
+				response:   json.RawMessage(`{"result": {"serverInfo": {"kuzzle": {"version": "2.0.0"}}}}`),
+			},
+		},
+		{
+			name:    "Unknown version",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/_serverInfo",
+				response:   json.RawMessage(`{"result": {"serverInfo": {}}}`),
+			},
+		},
+		{
+			name:    "Forbidden",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 403,
+				url:        "http://kuzzle:7512",
+				route:      "/_serverInfo",
+				response:   json.RawMessage(`{"error": {"id": "security.rights.forbidden", "message": "Insufficient permissions to execute server:info"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleServerInfo().Schema, map[string]interface{}{})
+			diags := dataSourceKuzzleServerInfoRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("dataSourceKuzzleServerInfoRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := d.Get("version").(string); got != tt.wantVersion {
+				t.Errorf("dataSourceKuzzleServerInfoRead() version = %v, want %v", got, tt.wantVersion)
+			}
+			if got := d.Get("node_name").(string); got != tt.wantNodeName {
+				t.Errorf("dataSourceKuzzleServerInfoRead() node_name = %v, want %v", got, tt.wantNodeName)
+			}
+			if got := d.Get("api_routes").([]interface{}); !reflect.DeepEqual(got, tt.wantRoutes) {
+				t.Errorf("dataSourceKuzzleServerInfoRead() api_routes = %v, want %v", got, tt.wantRoutes)
+			}
+		})
+	}
+}
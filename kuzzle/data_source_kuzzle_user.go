@@ -0,0 +1,80 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceKuzzleUser() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns an existing Kuzzle user, failing if it does not exist",
+
+		ReadContext: dataSourceKuzzleUserRead,
+
+		Schema: map[string]*schema.Schema{
+			"kuid": { // User unique identifier
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Kuzzle user identifier",
+			},
+			"profile_ids": { // Profiles of the user
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Identifiers of the profiles assigned to the user",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"content": { // Custom user content
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON content of the user, besides its profiles and Kuzzle metadata, with its keys sorted",
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	kuid := d.Get("kuid").(string)
+
+	var user struct {
+		Source map[string]json.RawMessage `json:"_source"`
+	}
+	err := config.query(ctx, http.MethodGet, userPath(kuid), nil, &user)
+	if isNotFound(err) {
+		return diag.Errorf("Kuzzle user %q does not exist", kuid)
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle user %q: %s", kuid, err)
+	}
+
+	profileIDs := []string{}
+	if raw, ok := user.Source["profileIds"]; ok {
+		if err := json.Unmarshal(raw, &profileIDs); err != nil {
+			return diag.Errorf("Error reading Kuzzle user %q profiles: %s", kuid, err)
+		}
+	}
+
+	// The profiles have their own attribute, and the metadata added by Kuzzle is not part of the content
+	delete(user.Source, "profileIds")
+	delete(user.Source, "_kuzzle_info")
+	raw, err := json.Marshal(user.Source)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	content, err := normalizeJSON(raw)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle user %q: %s", kuid, err)
+	}
+
+	d.SetId(kuid)
+	d.Set("content", content)
+	if err := d.Set("profile_ids", profileIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleUserRead(t *testing.T) {
+	tests := []struct {
+		name           string
+		wantErr        bool
+		wantProfileIDs []interface{}
+		wantContent    string
+		mock           Mock
+	}{
+		{
+			name:           "Success",
+			wantErr:        false,
+			wantProfileIDs: []interface{}{"editor", "default"},
+			wantContent:    `{"email":"ada@example.com","name":"Ada Lovelace"}`,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/users/ada",
+				response: json.RawMessage(`{"result": {"_id": "ada", "_source": {"profileIds": ["editor", "default"], "name": "Ada Lovelace", "email": "ada@example.com",
+					"_kuzzle_info": {"author": "-1", "createdAt": 1600000000000}}}}`),
+			},
+		},
+		{
+			name:           "Without content",
+			wantErr:        false,
+			wantProfileIDs: []interface{}{"default"},
+			wantContent:    `{}`,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/users/ada",
+				response:   json.RawMessage(`{"result": {"_id": "ada", "_source": {"profileIds": ["default"]}}}`),
+			},
+		},
+		{
+			name:    "Unknown user",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				url:        "http://kuzzle:7512",
+				route:      "/users/ada",
+				response:   json.RawMessage(`{"error": {"id": "security.user.not_found", "message": "User \"ada\" not found."}}`),
+			},
+		},
+		{
+			name:    "Unexpected profiles",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/users/ada",
+				response:   json.RawMessage(`{"result": {"_id": "ada", "_source": {"profileIds": "default"}}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleUser().Schema, map[string]interface{}{
+				"kuid": "ada",
+			})
+			diags := dataSourceKuzzleUserRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("dataSourceKuzzleUserRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := d.Get("profile_ids").([]interface{}); !reflect.DeepEqual(got, tt.wantProfileIDs) {
+				t.Errorf("dataSourceKuzzleUserRead() profile_ids = %v, want %v", got, tt.wantProfileIDs)
+			}
+			if got := d.Get("content").(string); got != tt.wantContent {
+				t.Errorf("dataSourceKuzzleUserRead() content = %v, want %v", got, tt.wantContent)
+			}
+		})
+	}
+}
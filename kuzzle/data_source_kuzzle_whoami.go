@@ -0,0 +1,74 @@
+package kuzzle
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// anonymousKuid is the identifier Kuzzle gives to unauthenticated users
+const anonymousKuid = "-1"
+
+func dataSourceKuzzleWhoami() *schema.Resource {
+	return &schema.Resource{
+		Description: "Returns the identity the provider is authenticated as",
+
+		ReadContext: dataSourceKuzzleWhoamiRead,
+
+		Schema: map[string]*schema.Schema{
+			"kuid": { // Kuzzle user unique identifier
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Kuzzle user unique identifier",
+			},
+			"profile_ids": { // Profiles of the user
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Identifiers of the profiles of the user",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"strategies": { // Authentication strategies of the user
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Authentication strategies the user has credentials for",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceKuzzleWhoamiRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	if config.authToken() == "" {
+		return diag.Errorf("Error reading the current Kuzzle identity: the provider is not authenticated, set api_key or login credentials")
+	}
+
+	var user struct {
+		ID     string `json:"_id"`
+		Source struct {
+			ProfileIDs []string `json:"profileIds"`
+		} `json:"_source"`
+		Strategies []string `json:"strategies"`
+	}
+	if err := config.query(ctx, http.MethodGet, "/users/_me", nil, &user); err != nil {
+		return diag.Errorf("Error reading the current Kuzzle identity: %s", err)
+	}
+
+	if user.ID == "" || user.ID == anonymousKuid {
+		return diag.Errorf("Error reading the current Kuzzle identity: Kuzzle considers the provider anonymous")
+	}
+
+	d.SetId(user.ID)
+	d.Set("kuid", user.ID)
+	if err := d.Set("profile_ids", user.Source.ProfileIDs); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("strategies", user.Strategies); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
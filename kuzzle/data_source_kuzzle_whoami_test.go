@@ -0,0 +1,108 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_dataSourceKuzzleWhoamiRead(t *testing.T) {
+	tests := []struct {
+		name           string
+		token          string
+		wantErr        bool
+		wantKuid       string
+		wantProfileIDs []interface{}
+		wantStrategies []interface{}
+		mock           Mock
+	}{
+		{
+			name:           "API key user",
+			token:          "myApiKey",
+			wantErr:        false,
+			wantKuid:       "ci-bot",
+			wantProfileIDs: []interface{}{"admin", "ci"},
+			wantStrategies: []interface{}{"local"},
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/users/_me",
+				response:   json.RawMessage(`{"result": {"_id": "ci-bot", "_source": {"profileIds": ["admin", "ci"], "name": "CI"}, "strategies": ["local"]}}`),
+			},
+		},
+		{
+			name:           "No profiles nor strategies",
+			token:          "myApiKey",
+			wantErr:        false,
+			wantKuid:       "ci-bot",
+			wantProfileIDs: []interface{}{},
+			wantStrategies: []interface{}{},
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/users/_me",
+				response:   json.RawMessage(`{"result": {"_id": "ci-bot", "_source": {}}}`),
+			},
+		},
+		{
+			name:    "Anonymous provider",
+			token:   "",
+			wantErr: true,
+		},
+		{
+			name:    "Anonymous for Kuzzle",
+			token:   "myApiKey",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/users/_me",
+				response:   json.RawMessage(`{"result": {"_id": "-1", "_source": {"profileIds": ["anonymous"]}}}`),
+			},
+		},
+		{
+			name:    "Unexpected profiles",
+			token:   "myApiKey",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/users/_me",
+				response:   json.RawMessage(`{"result": {"_id": "ci-bot", "_source": {"profileIds": "admin"}}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, dataSourceKuzzleWhoami().Schema, map[string]interface{}{})
+			diags := dataSourceKuzzleWhoamiRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512", Token: tt.token})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("dataSourceKuzzleWhoamiRead() diags = %v, wantErr %v", diags, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := d.Get("kuid").(string); got != tt.wantKuid {
+				t.Errorf("dataSourceKuzzleWhoamiRead() kuid = %v, want %v", got, tt.wantKuid)
+			}
+			if got := d.Get("profile_ids").([]interface{}); !reflect.DeepEqual(got, tt.wantProfileIDs) {
+				t.Errorf("dataSourceKuzzleWhoamiRead() profile_ids = %v, want %v", got, tt.wantProfileIDs)
+			}
+			if got := d.Get("strategies").([]interface{}); !reflect.DeepEqual(got, tt.wantStrategies) {
+				t.Errorf("dataSourceKuzzleWhoamiRead() strategies = %v, want %v", got, tt.wantStrategies)
+			}
+		})
+	}
+}
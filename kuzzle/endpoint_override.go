@@ -0,0 +1,121 @@
+package kuzzle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// endpointOverrides are the configurations built for the endpoint overrides of the resources, by endpoint,
+// so that each endpoint is checked and logged into once per run
+type endpointOverrides struct {
+	mu      sync.Mutex
+	configs map[string]*Config
+}
+
+// withEndpointOverride adds the endpoint_override attribute to a resource: when set,
+// the operations of the resource are sent to that endpoint instead of the provider one.
+// It wraps the other operation wrappers, so that version checks apply to the override server.
+func withEndpointOverride(r *schema.Resource) *schema.Resource {
+	r.Schema["endpoint_override"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		ForceNew: true,
+		Description: "Kuzzle endpoint URL the resource is managed on instead of the provider one, e.g. to manage resources of a secondary cluster without a provider alias. " +
+			"The TLS, proxy, timeout and headers settings of the provider are reused, the login credentials are used to log into it and API keys and JWTs are sent as is. " +
+			"Resources managed on an override endpoint cannot be imported",
+		ValidateFunc: validateEndpoint,
+	}
+
+	if r.CreateContext != nil {
+		r.CreateContext = schema.CreateContextFunc(overrideEndpoint(operation(r.CreateContext)))
+	}
+	if r.ReadContext != nil {
+		r.ReadContext = schema.ReadContextFunc(overrideEndpoint(operation(r.ReadContext)))
+	}
+	if r.UpdateContext != nil {
+		r.UpdateContext = schema.UpdateContextFunc(overrideEndpoint(operation(r.UpdateContext)))
+	}
+	if r.DeleteContext != nil {
+		r.DeleteContext = schema.DeleteContextFunc(overrideEndpoint(operation(r.DeleteContext)))
+	}
+
+	return r
+}
+
+// overrideEndpoint wraps an operation so that it is given the configuration of the resource endpoint override, if any
+func overrideEndpoint(op operation) operation {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		endpoint := d.Get("endpoint_override").(string)
+		config, err := meta.(*Config).forEndpoint(ctx, endpoint)
+		if err != nil {
+			return diag.Errorf("Error connecting to Kuzzle endpoint_override %s: %s", endpoint, err)
+		}
+		return op(ctx, d, config)
+	}
+}
+
+// forEndpoint returns the configuration sending requests to another endpoint, the configuration itself if endpoint
+// is empty or its own. It is built once per endpoint: its connection is checked, and it logs in with the provider
+// login credentials, if any, as the tokens of the provider endpoint may not be valid on another one.
+func (c *Config) forEndpoint(ctx context.Context, endpoint string) (*Config, error) {
+	if endpoint == "" || endpoint == c.Endpoint {
+		return c, nil
+	}
+
+	if c.overrides != nil {
+		c.overrides.mu.Lock()
+		defer c.overrides.mu.Unlock()
+
+		if override, ok := c.overrides.configs[endpoint]; ok {
+			return override, nil
+		}
+	}
+
+	options := c.clientOptions
+	options.endpoint = endpoint
+	if options.proxy != nil && isWebSocketEndpoint(endpoint) {
+		return nil, fmt.Errorf("proxy_url cannot be used with a WebSocket endpoint, use an HTTP one instead")
+	}
+
+	override := *c
+	override.Endpoint = endpoint
+	override.client = newHTTPClient(options)
+	override.clientOptions = options
+	override.overrides = nil
+	override.ServerVersion = ""
+	override.Token = c.authToken()
+	override.credentials = nil
+
+	if err := checkConnection(ctx, &override); err != nil {
+		return nil, err
+	}
+
+	if c.credentials != nil {
+		override.Token = ""
+		jwt, err := authenticate(ctx, &override, c.credentials.strategy, c.credentials.body)
+		if err != nil {
+			return nil, fmt.Errorf("authentication failed: %s", err)
+		}
+
+		override.Token = jwt
+		override.credentials = &credentials{strategy: c.credentials.strategy, body: c.credentials.body}
+		if c.logoutOnDone {
+			override.logoutOnTeardown()
+		}
+	}
+
+	override.serverVersion(ctx)
+
+	if c.overrides != nil {
+		if c.overrides.configs == nil {
+			c.overrides.configs = make(map[string]*Config)
+		}
+		c.overrides.configs[endpoint] = &override
+	}
+
+	return &override, nil
+}
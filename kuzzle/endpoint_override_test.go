@@ -0,0 +1,139 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// The operations of a resource with an endpoint override are sent to that endpoint, which is checked and logged into once
+func Test_withEndpointOverride(t *testing.T) {
+	tests := []struct {
+		name             string
+		endpointOverride string
+		endpoint         string
+		token            string
+		credentials      *credentials
+		wantToken        string
+	}{
+		{
+			name:             "No override",
+			endpointOverride: "",
+			endpoint:         "http://kuzzle:7512",
+			token:            "api-key",
+			wantToken:        "api-key",
+		},
+		{
+			name:             "API key sent as is",
+			endpointOverride: "http://secondary:7512",
+			endpoint:         "http://secondary:7512",
+			token:            "api-key",
+			wantToken:        "api-key",
+		},
+		{
+			name:             "Login credentials used again",
+			endpointOverride: "https://secondary:7443",
+			endpoint:         "https://secondary:7443",
+			token:            "primary-jwt",
+			credentials:      &credentials{strategy: "local", body: json.RawMessage(`{"password":"secret","username":"admin"}`)},
+			wantToken:        "secondary-jwt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+
+			if tt.endpointOverride != "" {
+				gock.New(tt.endpoint).
+					Get("/_healthcheck").
+					Reply(200).
+					JSON(json.RawMessage(`{"result": {"status": "green"}}`))
+				gock.New(tt.endpoint).
+					Get("/_serverInfo").
+					Reply(200).
+					JSON(json.RawMessage(`{"result": {"serverInfo": {"kuzzle": {"version": "2.14.0"}}}}`))
+			}
+			if tt.credentials != nil {
+				gock.New(tt.endpoint).
+					Post("/_login/local").
+					BodyString(`{"password":"secret","username":"admin"}`).
+					Reply(200).
+					JSON(json.RawMessage(`{"result": {"jwt": "secondary-jwt"}}`))
+			}
+			gock.New(tt.endpoint).
+				Post("/iot/_create").
+				MatchHeader("Authorization", "^Bearer "+tt.wantToken+"$").
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"acknowledged": true}}`))
+			gock.New(tt.endpoint).
+				Get("/iot/_exists").
+				MatchHeader("Authorization", "^Bearer "+tt.wantToken+"$").
+				Times(2).
+				Reply(200).
+				JSON(json.RawMessage(`{"result": true}`))
+
+			config := &Config{
+				Endpoint:    "http://kuzzle:7512",
+				Token:       tt.token,
+				credentials: tt.credentials,
+				overrides:   &endpointOverrides{},
+			}
+			r := Provider().ResourcesMap["kuzzle_index"]
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"index":             "iot",
+				"endpoint_override": tt.endpointOverride,
+			})
+			if diags := r.CreateContext(context.Background(), d, config); diags.HasError() {
+				t.Fatalf("kuzzle_index create diags = %v", diags)
+			}
+			if diags := r.ReadContext(context.Background(), d, config); diags.HasError() {
+				t.Fatalf("kuzzle_index read diags = %v", diags)
+			}
+			if !gock.IsDone() {
+				t.Errorf("kuzzle_index pending mocks = %v", gock.Pending())
+			}
+			if config.Endpoint != "http://kuzzle:7512" || config.authToken() != tt.token {
+				t.Errorf("kuzzle_index changed the provider configuration: %v", config)
+			}
+		})
+	}
+}
+
+func Test_withEndpointOverrideValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		wantErr  bool
+	}{
+		{name: "HTTPS endpoint", endpoint: "https://secondary:7443", wantErr: false},
+		{name: "WebSocket endpoint", endpoint: "ws://secondary:7512", wantErr: false},
+		{name: "Unsupported scheme", endpoint: "ftp://secondary:7512", wantErr: true},
+		{name: "No host", endpoint: "http://", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validate := Provider().ResourcesMap["kuzzle_index"].Schema["endpoint_override"].ValidateFunc
+			if _, errs := validate(tt.endpoint, "endpoint_override"); (len(errs) > 0) != tt.wantErr {
+				t.Errorf("endpoint_override validation errors = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+// A proxy cannot reach a WebSocket endpoint override, which is reported before any request is sent
+func Test_forEndpointProxy(t *testing.T) {
+	defer gock.Off()
+
+	proxy, err := parseProxyURL("http://proxy:3128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{Endpoint: "http://kuzzle:7512", clientOptions: clientOptions{endpoint: "http://kuzzle:7512", proxy: proxy}}
+
+	if _, err := config.forEndpoint(context.Background(), "ws://secondary:7512"); err == nil {
+		t.Errorf("forEndpoint() error = nil, want an error")
+	}
+}
@@ -0,0 +1,60 @@
+package kuzzle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// experimental marks a resource or data source as unstable: it stays declared in the provider schema,
+// as Terraform needs it before the provider is configured, but using it fails unless enable_experimental is set
+func experimental(name string, r *schema.Resource) *schema.Resource {
+	r.Description = "Experimental, requires enable_experimental. " + r.Description
+
+	if r.CreateContext != nil {
+		r.CreateContext = schema.CreateContextFunc(requireExperimental(name, operation(r.CreateContext)))
+	}
+	if r.ReadContext != nil {
+		r.ReadContext = schema.ReadContextFunc(requireExperimental(name, operation(r.ReadContext)))
+	}
+	if r.UpdateContext != nil {
+		r.UpdateContext = schema.UpdateContextFunc(requireExperimental(name, operation(r.UpdateContext)))
+	}
+	if r.DeleteContext != nil {
+		r.DeleteContext = schema.DeleteContextFunc(requireExperimental(name, operation(r.DeleteContext)))
+	}
+
+	if r.Importer != nil && r.Importer.StateContext != nil {
+		importState := r.Importer.StateContext
+		r.Importer = &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				if !meta.(*Config).Experimental {
+					return nil, experimentalError(name)
+				}
+				return importState(ctx, d, meta)
+			},
+		}
+	}
+
+	return r
+}
+
+// operation is the signature shared by the create, read, update and delete functions
+type operation func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics
+
+// requireExperimental wraps an operation of an experimental resource so that it fails unless experimental features are enabled
+func requireExperimental(name string, op operation) operation {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if !meta.(*Config).Experimental {
+			return diag.FromErr(experimentalError(name))
+		}
+		return op(ctx, d, meta)
+	}
+}
+
+// experimentalError explains how to opt into an experimental resource
+func experimentalError(name string) error {
+	return fmt.Errorf("%s is experimental, set enable_experimental = true in the Kuzzle provider configuration to use it", name)
+}
@@ -0,0 +1,100 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_experimentalDataSources(t *testing.T) {
+	tests := []struct {
+		name         string
+		experimental bool
+		wantErr      bool
+	}{
+		{name: "Disabled by default", experimental: false, wantErr: true},
+		{name: "Enabled", experimental: true, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.
+				New("http://kuzzle:7512").
+				Get("/iot/sensors/sensor-1").
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"_id": "sensor-1", "_version": 1, "_source": {"name": "sensor-1"}}}`))
+
+			dataSource := Provider().DataSourcesMap["kuzzle_document_versions"]
+			d := schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{
+				"index":       "iot",
+				"collection":  "sensors",
+				"document_id": "sensor-1",
+			})
+			diags := dataSource.ReadContext(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512", Experimental: tt.experimental})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("kuzzle_document_versions read diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr && gock.IsDone() {
+				t.Errorf("kuzzle_document_versions sent a request while experimental resources are disabled")
+			}
+		})
+	}
+}
+
+func Test_experimental(t *testing.T) {
+	var called []string
+	record := func(name string) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		return func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+			called = append(called, name)
+			return nil
+		}
+	}
+	r := experimental("kuzzle_reindex", &schema.Resource{
+		Description:   "Reindexes a collection",
+		CreateContext: record("create"),
+		ReadContext:   record("read"),
+		DeleteContext: record("delete"),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{},
+	})
+
+	if !strings.HasPrefix(r.Description, "Experimental") {
+		t.Errorf("experimental() description = %q, want it to be flagged as experimental", r.Description)
+	}
+	if r.UpdateContext != nil {
+		t.Errorf("experimental() added an update operation")
+	}
+
+	d := r.TestResourceData()
+	disabled := &Config{}
+	for _, diags := range []diag.Diagnostics{
+		r.CreateContext(context.Background(), d, disabled),
+		r.ReadContext(context.Background(), d, disabled),
+		r.DeleteContext(context.Background(), d, disabled),
+	} {
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, "enable_experimental") {
+			t.Errorf("experimental() diags = %v, want an error explaining how to enable it", diags)
+		}
+	}
+	if _, err := r.Importer.StateContext(context.Background(), d, disabled); err == nil {
+		t.Errorf("experimental() import succeeded while experimental resources are disabled")
+	}
+	if len(called) != 0 {
+		t.Errorf("experimental() called %v while experimental resources are disabled", called)
+	}
+
+	enabled := &Config{Experimental: true}
+	r.CreateContext(context.Background(), d, enabled)
+	r.ReadContext(context.Background(), d, enabled)
+	r.DeleteContext(context.Background(), d, enabled)
+	if strings.Join(called, ",") != "create,read,delete" {
+		t.Errorf("experimental() called %v, want create,read,delete", called)
+	}
+}
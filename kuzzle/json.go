@@ -0,0 +1,318 @@
+package kuzzle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// suppressEquivalentJSON ignores the differences between two JSON strings
+// that only differ by their formatting or the order of their keys
+func suppressEquivalentJSON(k string, old string, new string, d *schema.ResourceData) bool {
+	return equivalentJSON([]byte(old), []byte(new))
+}
+
+// equivalentJSON tells whether two JSON documents hold the same value
+func equivalentJSON(a []byte, b []byte) bool {
+	var aValue, bValue interface{}
+	if err := json.Unmarshal(a, &aValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &bValue); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+// normalizeJSON returns a JSON value with its object keys sorted and without insignificant whitespace,
+// so that it only changes with the value. A missing value is normalized to an empty object.
+// Values nested deeper than json_max_depth are rejected before being decoded.
+func normalizeJSON(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "{}", nil
+	}
+
+	if err := checkJSONDepth(raw, int(atomic.LoadInt64(&jsonMaxDepth))); err != nil {
+		return "", err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", err
+	}
+
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(normalized), nil
+}
+
+// defaultJSONMaxDepth is the default maximum nesting depth of the JSON values normalized by the provider
+const defaultJSONMaxDepth = 100
+
+// jsonMaxDepth is the maximum nesting depth of the JSON values normalized by the provider, unlimited if not positive.
+// It is set from the provider configuration and read atomically, as normalization runs concurrently.
+var jsonMaxDepth int64 = defaultJSONMaxDepth
+
+// setJSONMaxDepth sets the maximum nesting depth of the JSON values normalized by the provider
+func setJSONMaxDepth(depth int) {
+	atomic.StoreInt64(&jsonMaxDepth, int64(depth))
+}
+
+// checkJSONDepth fails when a JSON value nests objects and arrays deeper than max, without decoding it,
+// so that pathological values are rejected before any time is spent on them. Brackets within strings are ignored.
+func checkJSONDepth(raw []byte, max int) error {
+	if max <= 0 {
+		return nil
+	}
+
+	depth := 0
+	inString, escaped := false, false
+	for _, b := range raw {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && b == '\\':
+			escaped = true
+		case b == '"':
+			inString = !inString
+		case inString:
+		case b == '{' || b == '[':
+			depth++
+			if depth > max {
+				return fmt.Errorf("JSON value is nested deeper than %d levels, raise json_max_depth in the provider configuration if this is expected", max)
+			}
+		case b == '}' || b == ']':
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// knownFields returns a remote JSON object restricted to the top level fields of the known JSON object,
+// so that the fields added by Kuzzle are not reported as drift.
+// Every field is kept when nothing is known, e.g. on import.
+func knownFields(known string, remote map[string]interface{}) (string, error) {
+	var knownObject map[string]interface{}
+	if known != "" {
+		if err := json.Unmarshal([]byte(known), &knownObject); err != nil {
+			return "", err
+		}
+	}
+
+	fields := remote
+	if knownObject != nil {
+		fields = make(map[string]interface{}, len(knownObject))
+		for field := range knownObject {
+			if value, ok := remote[field]; ok {
+				fields[field] = value
+			}
+		}
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeJSON(raw)
+}
+
+// validateJSON returns a plan time validation of a JSON string attribute:
+// the string must be valid JSON, and its decoded value must pass the shape check, if any
+func validateJSON(shape func(value interface{}) error) schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		var value interface{}
+		if err := json.Unmarshal([]byte(v.(string)), &value); err != nil {
+			return diag.Diagnostics{{
+				Severity:      diag.Error,
+				Summary:       "Invalid JSON",
+				Detail:        fmt.Sprintf("The value cannot be parsed as JSON: %s", err),
+				AttributePath: path,
+			}}
+		}
+
+		if shape == nil {
+			return nil
+		}
+
+		if err := shape(value); err != nil {
+			return diag.Diagnostics{{
+				Severity:      diag.Error,
+				Summary:       "Unexpected JSON structure",
+				Detail:        err.Error(),
+				AttributePath: path,
+			}}
+		}
+
+		return nil
+	}
+}
+
+// validateJSONMap returns a plan time validation of a map attribute of JSON strings,
+// each value being checked as validateJSON does
+func validateJSONMap(shape func(value interface{}) error) schema.SchemaValidateDiagFunc {
+	validate := validateJSON(shape)
+
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		var diags diag.Diagnostics
+		for key, value := range v.(map[string]interface{}) {
+			diags = append(diags, validate(value, path.IndexString(key))...)
+		}
+
+		return diags
+	}
+}
+
+// jsonObject checks that a decoded JSON value is an object
+func jsonObject(value interface{}) error {
+	if _, ok := value.(map[string]interface{}); !ok {
+		return fmt.Errorf("expected a JSON object, got %s", jsonTypeName(value))
+	}
+
+	return nil
+}
+
+// jsonDocuments checks that a decoded JSON value is an array of documents,
+// each one being an object with a "body" object and an optional "_id" string
+func jsonDocuments(value interface{}) error {
+	documents, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected a JSON array of documents, got %s", jsonTypeName(value))
+	}
+
+	for i, document := range documents {
+		fields, ok := document.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("document %d: expected an object, got %s", i, jsonTypeName(document))
+		}
+		if _, ok := fields["body"].(map[string]interface{}); !ok {
+			return fmt.Errorf("document %d: expected a \"body\" object, got %s", i, jsonTypeName(fields["body"]))
+		}
+		if id, ok := fields["_id"]; ok {
+			if _, ok := id.(string); !ok {
+				return fmt.Errorf("document %d: expected an \"_id\" string, got %s", i, jsonTypeName(id))
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonFixtures checks that a decoded JSON value is fixtures, i.e. an object of indexes,
+// each one being an object of collections holding an array of bulk actions and documents
+func jsonFixtures(value interface{}) error {
+	indexes, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected a JSON object of indexes, got %s", jsonTypeName(value))
+	}
+
+	for index, collections := range indexes {
+		collectionsObject, ok := collections.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("index %s: expected an object of collections, got %s", index, jsonTypeName(collections))
+		}
+		for collection, documents := range collectionsObject {
+			if _, ok := documents.([]interface{}); !ok {
+				return fmt.Errorf("collection %s/%s: expected an array of bulk actions and documents, got %s", index, collection, jsonTypeName(documents))
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonMappingsTree checks that a decoded JSON value is a mappings tree, i.e. an object of indexes,
+// each one being an object holding the mappings object of each collection
+func jsonMappingsTree(value interface{}) error {
+	indexes, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected a JSON object of indexes, got %s", jsonTypeName(value))
+	}
+
+	for index, collections := range indexes {
+		collectionsObject, ok := collections.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("index %s: expected an object of collections, got %s", index, jsonTypeName(collections))
+		}
+		for collection, mappings := range collectionsObject {
+			if _, ok := mappings.(map[string]interface{}); !ok {
+				return fmt.Errorf("collection %s/%s: expected a mappings object, got %s", index, collection, jsonTypeName(mappings))
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonSecurities checks that a decoded JSON value is a security bundle, i.e. an object with optional
+// "roles", "profiles" and "users" objects, each one holding a definition object by identifier
+func jsonSecurities(value interface{}) error {
+	bundle, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected a JSON object of roles, profiles and users, got %s", jsonTypeName(value))
+	}
+
+	for kind, definitions := range bundle {
+		if kind != "roles" && kind != "profiles" && kind != "users" {
+			return fmt.Errorf("unexpected %q, expected roles, profiles or users", kind)
+		}
+		definitionsObject, ok := definitions.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object by identifier, got %s", kind, jsonTypeName(definitions))
+		}
+		for id, definition := range definitionsObject {
+			if _, ok := definition.(map[string]interface{}); !ok {
+				return fmt.Errorf("%s %q: expected an object, got %s", kind, id, jsonTypeName(definition))
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonTypeName returns the JSON type of a decoded value, for error messages
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return "null"
+	}
+}
+
+// decodeJSON decodes the JSON value at the start of data into v. Trailing whitespace is ignored,
+// while any other trailing data, e.g. a second value appended by a gateway, is reported as such.
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if trailing := bytes.TrimSpace(data[decoder.InputOffset():]); len(trailing) > 0 {
+		if len(trailing) > 32 {
+			trailing = append(trailing[:32:32], "..."...)
+		}
+		return fmt.Errorf("unexpected data after the JSON value: %q", trailing)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package kuzzle
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func Test_suppressEquivalentJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want bool
+	}{
+		{name: "Key order", old: `{"password":"s3cr3t","username":"ada"}`, new: `{"username": "ada", "password": "s3cr3t"}`, want: true},
+		{name: "Different value", old: `{"username":"ada"}`, new: `{"username":"grace"}`, want: false},
+		{name: "Invalid JSON", old: `{"username":"ada"}`, new: `{"username":`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressEquivalentJSON("body", tt.old, tt.new, nil); got != tt.want {
+				t.Errorf("suppressEquivalentJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateJSONAtPlan(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantSummary string
+	}{
+		{name: "Object", body: `{"username": "ada", "password": "s3cr3t"}`, wantSummary: ""},
+		{name: "Malformed", body: `{"username": "ada",`, wantSummary: "Invalid JSON"},
+		{name: "Array instead of object", body: `["ada", "s3cr3t"]`, wantSummary: "Unexpected JSON structure"},
+		{name: "String instead of object", body: `"ada:s3cr3t"`, wantSummary: "Unexpected JSON structure"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := resourceKuzzleCredentials().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+				"kuid":     "ada",
+				"strategy": "local",
+				"body":     tt.body,
+			}))
+
+			if tt.wantSummary == "" {
+				if diags.HasError() {
+					t.Errorf("Validate() diags = %v, want none", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || diags[0].Severity != diag.Error || diags[0].Summary != tt.wantSummary {
+				t.Errorf("Validate() diags = %v, want a %q error", diags, tt.wantSummary)
+				return
+			}
+			if len(diags[0].AttributePath) == 0 {
+				t.Errorf("Validate() diagnostic has no attribute path")
+			}
+		})
+	}
+}
+
+func Test_validateJSONAttributesAtPlan(t *testing.T) {
+	documentsQuery := func(query string) diag.Diagnostics {
+		return dataSourceKuzzleDocuments().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+			"index":      "iot",
+			"collection": "sensors",
+			"query":      query,
+		}))
+	}
+	providerVolatile := func(volatile string) diag.Diagnostics {
+		return Provider().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+			"endpoint": "http://kuzzle:7512",
+			"volatile": volatile,
+		}))
+	}
+
+	bulkDocuments := func(body string) diag.Diagnostics {
+		return resourceKuzzleBulkDocuments().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+			"index":      "iot",
+			"collection": "sensors",
+			"documents":  map[string]interface{}{"sensor-1": `{"name": "lobby"}`, "sensor-2": body},
+		}))
+	}
+
+	tests := []struct {
+		name        string
+		diags       diag.Diagnostics
+		wantSummary string
+	}{
+		{name: "Query object", diags: documentsQuery(`{"match_all": {}}`), wantSummary: ""},
+		{name: "Malformed query", diags: documentsQuery(`{"match_all": {}`), wantSummary: "Invalid JSON"},
+		{name: "Query array", diags: documentsQuery(`[{"match_all": {}}]`), wantSummary: "Unexpected JSON structure"},
+		{name: "Volatile object", diags: providerVolatile(`{"source": "terraform"}`), wantSummary: ""},
+		{name: "Malformed volatile", diags: providerVolatile(`{"source": }`), wantSummary: "Invalid JSON"},
+		{name: "Volatile string", diags: providerVolatile(`"terraform"`), wantSummary: "Unexpected JSON structure"},
+		{name: "Document objects", diags: bulkDocuments(`{"name": "garage"}`), wantSummary: ""},
+		{name: "Malformed document", diags: bulkDocuments(`{"name": "garage"`), wantSummary: "Invalid JSON"},
+		{name: "Document array", diags: bulkDocuments(`["garage"]`), wantSummary: "Unexpected JSON structure"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantSummary == "" {
+				if tt.diags.HasError() {
+					t.Errorf("Validate() diags = %v, want none", tt.diags)
+				}
+				return
+			}
+			if len(tt.diags) != 1 || tt.diags[0].Summary != tt.wantSummary || len(tt.diags[0].AttributePath) == 0 {
+				t.Errorf("Validate() diags = %v, want a %q error on the attribute", tt.diags, tt.wantSummary)
+			}
+		})
+	}
+}
+
+// Every JSON string attribute, or map of JSON strings, set by the user must be validated at plan time,
+// JSON attributes being recognized by their description
+func Test_jsonAttributesValidated(t *testing.T) {
+	provider := Provider()
+	schemas := map[string]map[string]*schema.Schema{"provider": provider.Schema}
+	for name, resource := range provider.ResourcesMap {
+		schemas["resource "+name] = resource.Schema
+	}
+	for name, dataSource := range provider.DataSourcesMap {
+		schemas["data source "+name] = dataSource.Schema
+	}
+
+	for owner, attributes := range schemas {
+		for name, attribute := range attributes {
+			if (attribute.Type != schema.TypeString && attribute.Type != schema.TypeMap) || (!attribute.Required && !attribute.Optional) {
+				continue
+			}
+			if strings.HasPrefix(attribute.Description, "JSON") && attribute.ValidateDiagFunc == nil {
+				t.Errorf("%s attribute %s is JSON but is not validated at plan time", owner, name)
+			}
+		}
+	}
+}
+
+func Test_jsonDocuments(t *testing.T) {
+	tests := []struct {
+		name      string
+		documents string
+		wantErr   bool
+	}{
+		{name: "Documents", documents: `[{"_id": "1", "body": {"name": "Ada"}}, {"body": {}}]`, wantErr: false},
+		{name: "Empty", documents: `[]`, wantErr: false},
+		{name: "Object", documents: `{"body": {}}`, wantErr: true},
+		{name: "Missing body", documents: `[{"_id": "1", "name": "Ada"}]`, wantErr: true},
+		{name: "Body not an object", documents: `[{"body": "Ada"}]`, wantErr: true},
+		{name: "Numeric id", documents: `[{"_id": 1, "body": {}}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.documents), &value); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if err := jsonDocuments(value); (err != nil) != tt.wantErr {
+				t.Errorf("jsonDocuments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_decodeJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "Single value", data: `{"result": {}}`, wantErr: false},
+		{name: "Trailing whitespace", data: "{\"result\": {}}\n\n  ", wantErr: false},
+		{name: "Trailing value", data: `{"result": {}} {"result": {}}`, wantErr: true},
+		{name: "Trailing garbage", data: `{"result": {}}garbage`, wantErr: true},
+		{name: "Invalid", data: `{"result": `, wantErr: true},
+		{name: "Empty", data: ``, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v map[string]interface{}
+			if err := decodeJSON([]byte(tt.data), &v); (err != nil) != tt.wantErr {
+				t.Errorf("decodeJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_normalizeJSONMaxDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxDepth int
+		raw      string
+		want     string
+		wantErr  bool
+	}{
+		{name: "Within the limit", maxDepth: 3, raw: `{"a": [{"b": 1}]}`, want: `{"a":[{"b":1}]}`, wantErr: false},
+		{name: "Too deep", maxDepth: 3, raw: `{"a": [{"b": [1]}]}`, wantErr: true},
+		{name: "Deeply nested default", maxDepth: defaultJSONMaxDepth, raw: strings.Repeat("[", 1000) + strings.Repeat("]", 1000), wantErr: true},
+		{name: "Brackets in strings", maxDepth: 1, raw: `{"a": "[[{\"[{"}`, want: `{"a":"[[{\"[{"}`, wantErr: false},
+		{name: "No limit", maxDepth: 0, raw: strings.Repeat("[", 1000) + strings.Repeat("]", 1000), want: strings.Repeat("[", 1000) + strings.Repeat("]", 1000), wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setJSONMaxDepth(tt.maxDepth)
+			defer setJSONMaxDepth(defaultJSONMaxDepth)
+
+			got, err := normalizeJSON(json.RawMessage(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "json_max_depth") {
+				t.Errorf("normalizeJSON() error = %v, want a hint about json_max_depth", err)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
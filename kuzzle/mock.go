@@ -3,6 +3,7 @@ package kuzzle
 type Mock struct {
 	enabled    bool
 	statusCode int
+	method     string
 	url        string
 	route      string
 	response   interface{}
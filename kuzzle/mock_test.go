@@ -0,0 +1,67 @@
+package kuzzle
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// registerMocks registers the enabled mocks in gock, in order
+func registerMocks(mocks []Mock) {
+	for _, mock := range mocks {
+		if !mock.enabled {
+			continue
+		}
+
+		req := gock.New(mock.url).Path(mock.route)
+		req.Method = mock.method
+		if req.Method == "" {
+			req.Method = http.MethodGet
+		}
+
+		res := req.Reply(mock.statusCode)
+		if mock.response != nil {
+			res.JSON(mock.response)
+		}
+	}
+}
+
+// mockServerInfo registers the server:info response of a Kuzzle server of the given version
+func mockServerInfo(version string) {
+	gock.
+		New("http://kuzzle:7512").
+		Get("/_serverInfo").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"result": map[string]interface{}{
+				"serverInfo": map[string]interface{}{
+					"kuzzle": map[string]interface{}{"version": version},
+				},
+			},
+		})
+}
+
+// updatedResourceData returns the resource data of an update, from the state of the old configuration to the new one
+func updatedResourceData(t *testing.T, r *schema.Resource, id string, old map[string]interface{}, new map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+
+	current := schema.TestResourceDataRaw(t, r.Schema, old)
+	current.SetId(id)
+	state := current.State()
+
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(new), nil)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("Data() error = %v", err)
+	}
+
+	return d
+}
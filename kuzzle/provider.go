@@ -3,51 +3,55 @@ package kuzzle
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
-type Config struct {
-	Endpoint string // Kuzzle endpoint URL
-	Token    string // API key or JWT
-}
+// Ways of sending a token to _checkToken, depending on the Kuzzle version
+const (
+	tokenCheckAuto   = "auto"   // Detect the way from the server version
+	tokenCheckToken  = "token"  // Token sent in the "token" body field
+	tokenCheckJWT    = "jwt"    // Token sent in the legacy "jwt" body field
+	tokenCheckHeader = "header" // Token sent in the Authorization header
+)
 
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"endpoint": { // Kuzzle endpoint URL
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Kuzzle endpoint URL",
-				DefaultFunc: schema.EnvDefaultFunc("KUZZLE_ENDPOINT", nil),
-				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
-					if v.(string) == "" {
-						errors = append(errors, fmt.Errorf("%q must be a non-empty string", k))
-					}
-
-					URL, err := url.Parse(v.(string))
-					if err != nil {
-						errors = append(errors, fmt.Errorf("%q must be a valid URL", k))
-					}
-
-					if URL.Scheme != "http" && URL.Scheme != "https" {
-						errors = append(errors, fmt.Errorf("%q must be a valid URL with http or https scheme", k))
-					}
-
-					return
-				},
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Kuzzle endpoint URL. With a ws or wss scheme, the connection, login and API key are checked over the Kuzzle WebSocket API, other requests being sent over HTTP to the same host",
+				DefaultFunc:  schema.EnvDefaultFunc("KUZZLE_ENDPOINT", nil),
+				ValidateFunc: validateEndpoint,
 			},
 			"api_key": { // API key or JWT
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Kuzzle API key",
-				DefaultFunc: schema.EnvDefaultFunc("KUZZLE_API_KEY", nil),
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				Description:   "Kuzzle API key, exclusive with the login credentials and jwt",
+				ConflictsWith: []string{"username", "password", "auth_credentials", "jwt"},
+				DefaultFunc:   schema.EnvDefaultFunc("KUZZLE_API_KEY", nil),
+			},
+			"jwt": { // Session token obtained elsewhere
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				Description:   "Kuzzle JWT obtained outside of the provider, e.g. from an SSO flow, used as is without logging in. It is exclusive with the login credentials and api_key, and is not revoked when the provider is done",
+				ConflictsWith: []string{"username", "password", "auth_credentials", "api_key"},
+				DefaultFunc:   schema.EnvDefaultFunc("KUZZLE_JWT", nil),
 			},
 			"username": { // Username
 				Type:        schema.TypeString,
@@ -58,15 +62,241 @@ func Provider() *schema.Provider {
 			"password": { // Password
 				Type:        schema.TypeString,
 				Optional:    true,
+				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("KUZZLE_PASSWORD", nil),
 				Description: "Kuzzle password",
 			},
+			"logout_on_done": { // Revoke the login token at the end of the run
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KUZZLE_LOGOUT_ON_DONE", true),
+				Description: "Log out when Terraform is done with the provider, to revoke the session opened with the login credentials instead of leaving it until it expires. API keys are never revoked",
+			},
+			"auth_strategy": { // Authentication strategy
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUZZLE_AUTH_STRATEGY", "local"),
+				Description:  "Authentication strategy used to log in, e.g. local or the name of a strategy plugin such as ldap",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"auth_credentials": { // Login body of the authentication strategy
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DefaultFunc:      schema.EnvDefaultFunc("KUZZLE_AUTH_CREDENTIALS", nil),
+				Description:      "JSON credentials sent to log in with the authentication strategy, taking precedence over username and password",
+				ValidateDiagFunc: validateJSON(jsonObject),
+			},
+			"insecure_skip_verify": { // Skip TLS certificate verification
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KUZZLE_INSECURE_SKIP_VERIFY", false),
+				Description: "Skip TLS certificate verification of HTTPS endpoints, e.g. for self-signed certificates",
+			},
+			"timeout": { // Request timeout
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUZZLE_TIMEOUT", 30),
+				Description:  "Time limit, in seconds, of each request sent to Kuzzle, 0 for no limit",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"proxy_url": { // Proxy to reach Kuzzle
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KUZZLE_PROXY", ""),
+				Description: "URL of the HTTP, HTTPS or SOCKS5 proxy used to reach Kuzzle, e.g. socks5://proxy:1080. The HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used when empty",
+			},
+			"ca_cert": { // PEM-encoded CA certificates
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("KUZZLE_CA_CERT", nil),
+				Description:   "PEM-encoded certificate authorities trusted to verify HTTPS endpoints, instead of the system ones",
+				ConflictsWith: []string{"ca_cert_file"},
+			},
+			"ca_cert_file": { // Path of the PEM-encoded CA certificates
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("KUZZLE_CA_CERT_FILE", nil),
+				Description:   "Path of a file holding PEM-encoded certificate authorities trusted to verify HTTPS endpoints",
+				ConflictsWith: []string{"ca_cert"},
+			},
+			"token_check_mode": { // How the API key is sent to _checkToken
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUZZLE_TOKEN_CHECK_MODE", tokenCheckAuto),
+				Description:  "How the API key is sent to Kuzzle to check its validity: auto (the legacy jwt field for servers detected as older than 2.0.0, the token field otherwise), token, jwt or header",
+				ValidateFunc: validation.StringInSlice([]string{tokenCheckAuto, tokenCheckToken, tokenCheckJWT, tokenCheckHeader}, false),
+			},
+			"rate_limit_max_wait": { // Budget for rate limited requests
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUZZLE_RATE_LIMIT_MAX_WAIT", 60),
+				Description:  "Maximum total time, in seconds, spent waiting before retrying rate limited (429) requests",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"json_max_depth": { // Nesting limit of JSON values
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUZZLE_JSON_MAX_DEPTH", defaultJSONMaxDepth),
+				Description:  "Maximum nesting depth of the JSON mappings, documents and security definitions the provider normalizes, deeper values being rejected. 0 for no limit",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_retries": { // Retries after transient failures
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUZZLE_MAX_RETRIES", 3),
+				Description:  "Number of times idempotent requests are retried, with an exponential backoff, after a connection error or a 502, 503 or 504 response",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_concurrent_requests": { // Limit of requests in flight
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUZZLE_MAX_CONCURRENT_REQUESTS", 0),
+				Description:  "Maximum number of requests sent to Kuzzle at the same time, 0 for no limit",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"summary_file": { // Operation summary path
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KUZZLE_SUMMARY_FILE", ""),
+				Description: "Path of a JSON file where the counts of created, updated and deleted objects, requests and retries are written once the provider shuts down",
+			},
+			"healthcheck_path": { // Route probed to check the connection
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KUZZLE_HEALTHCHECK_PATH", ""),
+				Description: "Route probed to check that Kuzzle is healthy, /_healthcheck by default, falling back to / for Kuzzle versions without it. Not used with WebSocket endpoints",
+			},
+			"wait_for_ready": { // Time to wait for Kuzzle to be healthy
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUZZLE_WAIT_FOR_READY", 0),
+				Description:  "Maximum time, in seconds, spent polling the healthcheck until Kuzzle is ready, e.g. while it boots next to Terraform, 0 to fail right away",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"enable_experimental": { // Opt into unstable resources
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KUZZLE_ENABLE_EXPERIMENTAL", false),
+				Description: "Enable the experimental resources and data sources, whose behavior may change in a future version",
+			},
+			"headers": { // Custom headers
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Custom headers sent with every request, e.g. for an API gateway in front of Kuzzle. Headers set by the provider take precedence",
+			},
+			"volatile": { // Metadata of write requests
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("KUZZLE_VOLATILE", nil),
+				Description:      "JSON object of volatile metadata attached to every write request, e.g. {\"source\": \"terraform\"}, showing up in Kuzzle logs and real-time notifications. It is best-effort metadata for auditing, not part of the managed state",
+				ValidateDiagFunc: validateJSON(jsonObject),
+			},
+			"refresh": { // Default refresh mode of security writes
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUZZLE_REFRESH", refreshWaitFor),
+				Description:  "Default refresh mode of security writes: wait_for to return once the change is visible, so that dependent resources find it, or false to return right away",
+				ValidateFunc: validation.StringInSlice([]string{refreshWaitFor, refreshFalse}, false),
+			},
+			"run_id_header": { // Header carrying the Terraform run id
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KUZZLE_RUN_ID_HEADER", "X-Terraform-Run-Id"),
+				Description: "Header used to send the Terraform run id with every request, for audit correlation",
+			},
 		},
 
-		ResourcesMap: map[string]*schema.Resource{},
+		ResourcesMap: map[string]*schema.Resource{
+			"kuzzle_api_action":                resourceKuzzleAPIAction(),
+			"kuzzle_api_key":                   requiresVersion("kuzzle_api_key", "2.1.0", resourceKuzzleAPIKey()),
+			"kuzzle_bulk_documents":            resourceKuzzleBulkDocuments(),
+			"kuzzle_collection":                resourceKuzzleCollection(),
+			"kuzzle_collection_import":         resourceKuzzleCollectionImport(),
+			"kuzzle_collection_mapping":        resourceKuzzleCollectionMapping(),
+			"kuzzle_collection_settings":       requiresVersion("kuzzle_collection_settings", "2.10.0", resourceKuzzleCollectionSettings()),
+			"kuzzle_collection_specifications": resourceKuzzleCollectionSpecifications(),
+			"kuzzle_credentials":               resourceKuzzleCredentials(),
+			"kuzzle_documents":                 resourceKuzzleDocuments(),
+			"kuzzle_first_admin":               resourceKuzzleFirstAdmin(),
+			"kuzzle_fixtures":                  resourceKuzzleFixtures(),
+			"kuzzle_index":                     resourceKuzzleIndex(),
+			"kuzzle_mappings":                  resourceKuzzleMappings(),
+			"kuzzle_plugin_configuration":      resourceKuzzlePluginConfiguration(),
+			"kuzzle_profile":                   resourceKuzzleProfile(),
+			"kuzzle_role":                      resourceKuzzleRole(),
+			"kuzzle_securities":                resourceKuzzleSecurities(),
+			"kuzzle_security_mapping":          resourceKuzzleSecurityMapping(),
+			"kuzzle_user":                      resourceKuzzleUser(),
+			"kuzzle_user_profile_attachment":   resourceKuzzleUserProfileAttachment(),
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"kuzzle_can":               dataSourceKuzzleCan(),
+			"kuzzle_collection":        dataSourceKuzzleCollection(),
+			"kuzzle_collection_export": dataSourceKuzzleCollectionExport(),
+			"kuzzle_collection_sample": dataSourceKuzzleCollectionSample(),
+			"kuzzle_collections":       dataSourceKuzzleCollections(),
+			"kuzzle_document":          dataSourceKuzzleDocument(),
+			"kuzzle_documents":         dataSourceKuzzleDocuments(),
+			"kuzzle_document_versions": experimental("kuzzle_document_versions", dataSourceKuzzleDocumentVersions()),
+			"kuzzle_field_cardinality": dataSourceKuzzleFieldCardinality(),
+			"kuzzle_indexes":           dataSourceKuzzleIndexes(),
+			"kuzzle_profile":           dataSourceKuzzleProfile(),
+			"kuzzle_profile_users":     dataSourceKuzzleProfileUsers(),
+			"kuzzle_provider_config":   dataSourceKuzzleProviderConfig(),
+			"kuzzle_role":              dataSourceKuzzleRole(),
+			"kuzzle_search":            dataSourceKuzzleSearch(),
+			"kuzzle_security_status":   dataSourceKuzzleSecurityStatus(),
+			"kuzzle_server_info":       dataSourceKuzzleServerInfo(),
+			"kuzzle_user":              dataSourceKuzzleUser(),
+			"kuzzle_whoami":            dataSourceKuzzleWhoami(),
+		},
 
 		ConfigureContextFunc: providerConfigure,
 	}
+
+	for _, r := range p.ResourcesMap {
+		withEndpointOverride(r)
+	}
+
+	return p
+}
+
+// validateEndpoint checks that the endpoint is an HTTP, HTTPS, WS or WSS URL with a host,
+// IPv6 hosts being written between brackets, e.g. http://[::1]:7512.
+// The endpoint may have a base path, routes being joined to it, but no query string or fragment.
+// Only the first problem is reported, as the following checks are meaningless on an unparsable URL.
+func validateEndpoint(v interface{}, k string) (ws []string, errors []error) {
+	if v.(string) == "" {
+		return nil, []error{fmt.Errorf("%q must be a non-empty string", k)}
+	}
+
+	URL, err := url.Parse(v.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%q must be a valid URL, IPv6 hosts between brackets: %s", k, err)}
+	}
+
+	switch URL.Scheme {
+	case "http", "https", "ws", "wss":
+	default:
+		return nil, []error{fmt.Errorf("%q must be a valid URL with http, https, ws or wss scheme, e.g. http://kuzzle:7512", k)}
+	}
+
+	if URL.Opaque != "" || URL.Hostname() == "" {
+		return nil, []error{fmt.Errorf("%q must be a valid URL with a host", k)}
+	}
+
+	if !strings.HasPrefix(URL.Host, "[") && strings.Contains(URL.Hostname(), ":") {
+		return nil, []error{fmt.Errorf("%q must have its IPv6 host between brackets, e.g. http://[::1]:7512", k)}
+	}
+
+	if URL.RawQuery != "" || URL.Fragment != "" || strings.HasSuffix(v.(string), "?") || strings.HasSuffix(v.(string), "#") {
+		return nil, []error{fmt.Errorf("%q must not have a query string or fragment, they would be dropped from the Kuzzle routes", k)}
+	}
+
+	return nil, nil
 }
 
 // providerConfigure is called to configure the provider.
@@ -77,136 +307,525 @@ func providerConfigure(
 ) (config interface{}, diags diag.Diagnostics) {
 	endpoint := d.Get("endpoint").(string)
 	apiKey := d.Get("api_key").(string)
+	jwt := d.Get("jwt").(string)
 	username := d.Get("username").(string)
 	password := d.Get("password").(string)
 
-	err := checkConnection(endpoint)
+	// Environment variables escape the plan time conflict check, the methods are checked again once resolved
+	loginBody := loginCredentials(d.Get("auth_credentials").(string), username, password)
+	methods := 0
+	for _, set := range []bool{apiKey != "", jwt != "", loginBody != nil} {
+		if set {
+			methods++
+		}
+	}
+	if methods > 1 {
+		return nil, diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Conflicting Kuzzle authentication methods",
+			Detail:   "api_key, jwt, and username and password or auth_credentials cannot be used together, set only one of them.",
+		}}
+	}
+
+	runID, err := resolveRunID()
 	if err != nil {
-		return nil, diag.Errorf("Error connecting to Kuzzle: %s", err)
+		return nil, diag.Errorf("Error generating Terraform run id: %s", err)
 	}
 
-	// If we have username/password, try to authenticate
-	if username != "" && password != "" {
-		jwt, err := tryAuthenticate(endpoint, username, password)
+	insecureSkipVerify := d.Get("insecure_skip_verify").(bool)
+	if insecureSkipVerify && isSecureEndpoint(endpoint) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Kuzzle TLS certificate verification disabled",
+			Detail:   "insecure_skip_verify is enabled: the identity of the Kuzzle server is not verified.",
+		})
+	}
+
+	headers, headerDiags := customHeaders(d.Get("headers").(map[string]interface{}), d.Get("run_id_header").(string))
+	diags = append(diags, headerDiags...)
+
+	volatile, err := compactVolatile(d.Get("volatile").(string))
+	if err != nil {
+		diags = append(diags, diag.Errorf("Invalid Kuzzle volatile metadata: %s", err)...)
+	}
+
+	rootCAs, caDiags := loadRootCAs(d, insecureSkipVerify)
+	diags = append(diags, caDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var proxy *url.URL
+	if proxyURL := d.Get("proxy_url").(string); proxyURL != "" {
+		proxy, err = parseProxyURL(proxyURL)
 		if err != nil {
-			diags = append(diags, diag.Diagnostic{
-				Severity: diag.Error,
-				Summary:  "Kuzzle authentication failed",
-				Detail:   err.Error(),
-			})
-			return
+			return nil, append(diags, diag.Errorf("Invalid Kuzzle proxy_url: %s", err)...)
 		}
-
-		config = &Config{
-			Endpoint: endpoint,
-			Token:    jwt,
+		if isWebSocketEndpoint(endpoint) {
+			return nil, append(diags, diag.Errorf("Kuzzle proxy_url cannot be used with a WebSocket endpoint, use an HTTP one instead")...)
 		}
 	}
 
-	// If no username/password pair is provided, we try to check the API key validity
-	if apiKey != "" {
-		err := checkToken(endpoint, apiKey)
+	setJSONMaxDepth(d.Get("json_max_depth").(int))
+
+	options := clientOptions{
+		endpoint:           endpoint,
+		insecureSkipVerify: insecureSkipVerify,
+		rootCAs:            rootCAs,
+		timeout:            time.Duration(d.Get("timeout").(int)) * time.Second,
+		proxy:              proxy,
+	}
+	c := &Config{
+		client:        newHTTPClient(options),
+		clientOptions: options,
+		overrides:     &endpointOverrides{},
+		logoutOnDone:  d.Get("logout_on_done").(bool),
+
+		Endpoint:        endpoint,
+		RunID:           runID,
+		RunIDHeader:     d.Get("run_id_header").(string),
+		TokenCheckMode:  d.Get("token_check_mode").(string),
+		HealthcheckPath: d.Get("healthcheck_path").(string),
+		Experimental:    d.Get("enable_experimental").(bool),
+		Refresh:         d.Get("refresh").(string),
+		Headers:         headers,
+		Volatile:        volatile,
+
+		RateLimitMaxWait: time.Duration(d.Get("rate_limit_max_wait").(int)) * time.Second,
+		MaxRetries:       d.Get("max_retries").(int),
+
+		requests: newRequestSlots(d.Get("max_concurrent_requests").(int)),
+
+		summary: newOperationSummary(d.Get("summary_file").(string)),
+	}
+
+	if waitForReady := time.Duration(d.Get("wait_for_ready").(int)) * time.Second; waitForReady > 0 {
+		waited, err := waitUntilReady(ctx, c, waitForReady)
 		if err != nil {
+			return nil, append(diags, diag.Errorf("Error connecting to Kuzzle: %s", err)...)
+		}
+		if waited > 0 {
 			diags = append(diags, diag.Diagnostic{
-				Severity: diag.Error,
-				Summary:  "Kuzzle provided API key is invalid",
-				Detail:   err.Error(),
+				Severity: diag.Warning,
+				Summary:  "Kuzzle was not ready right away",
+				Detail:   fmt.Sprintf("Kuzzle became ready after %s.", waited.Round(time.Second)),
 			})
-			return
+		}
+	} else if err := checkConnection(ctx, c); err != nil {
+		return nil, append(diags, diag.Errorf("Error connecting to Kuzzle: %s", err)...)
+	}
+
+	// If we have credentials, try to authenticate
+	strategy := d.Get("auth_strategy").(string)
+	if loginBody != nil {
+		jwt, err := authenticate(ctx, c, strategy, loginBody)
+		if err != nil {
+			return nil, append(diags, errorDiagnostic("Kuzzle authentication failed", err))
+		}
+
+		c.Token = jwt
+		c.credentials = &credentials{strategy: strategy, body: loginBody}
+
+		// Only the session opened by the provider is revoked, API keys are left alone
+		if c.logoutOnDone {
+			c.logoutOnTeardown()
+		}
+	} else if apiKey != "" {
+		// If no username/password pair is provided, we try to check the API key validity
+		err := checkToken(ctx, c, apiKey, tokenKindAPIKey)
+		if err != nil {
+			return nil, append(diags, errorDiagnostic("Kuzzle provided API key is invalid", err))
 		}
 
-		config = &Config{
-			Endpoint: endpoint,
-			Token:    apiKey,
+		c.Token = apiKey
+	} else if jwt != "" {
+		// A JWT obtained elsewhere is checked like an API key, it may have expired in the meantime
+		err := checkToken(ctx, c, jwt, tokenKindJWT)
+		if err != nil {
+			return nil, append(diags, errorDiagnostic("Kuzzle provided JWT is invalid", err))
 		}
+
+		c.Token = jwt
+		c.tokenKind = tokenKindJWT
 	}
 
+	// The server version is fetched once authenticated, as server:info may be denied to anonymous users,
+	// unless it was already fetched to check the token
+	c.serverVersion(ctx)
+
 	// If no authentication method is provided, we try to use anonymous authentication
-	if config == nil {
+	c.Anonymous = c.Token == ""
+	if c.Anonymous {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Warning,
 			Summary:  "Kuzzle authentication credentials not provided",
 			Detail:   "No authentication credentials provided. Using anonymous authentication...",
 		})
+	}
 
-		config = &Config{
-			Endpoint: endpoint,
+	return c, diags
+}
+
+// customHeaders converts the headers attribute, warning about the headers overridden by the provider
+func customHeaders(raw map[string]interface{}, runIDHeader string) (map[string]string, diag.Diagnostics) {
+	reserved := []string{"Accept", "Authorization", "Content-Type", runIDHeader}
+
+	var diags diag.Diagnostics
+	headers := make(map[string]string, len(raw))
+	for name, value := range raw {
+		headers[name] = value.(string)
+
+		for _, r := range reserved {
+			if r != "" && http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(r) {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "Kuzzle custom header overridden",
+					Detail:   fmt.Sprintf("The %s header is set by the provider, its value in headers may be ignored.", name),
+				})
+			}
 		}
 	}
 
-	return
+	return headers, diags
+}
+
+// compactVolatile checks that the volatile metadata is a JSON object and returns it on a single line,
+// as it is sent in a header. Values set through the environment are not validated at plan time.
+func compactVolatile(volatile string) (string, error) {
+	if volatile == "" {
+		return "", nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(volatile), &value); err != nil {
+		return "", err
+	}
+	if err := jsonObject(value); err != nil {
+		return "", err
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, []byte(volatile)); err != nil {
+		return "", err
+	}
+
+	return compacted.String(), nil
 }
 
-// checkConnection tests the connection to the Kuzzle server
-func checkConnection(endpoint string) error {
-	client := &http.Client{}
-	resp, err := client.Get(endpoint)
+// loadRootCAs returns the certificate authorities set by ca_cert or ca_cert_file, if any
+func loadRootCAs(d *schema.ResourceData, insecureSkipVerify bool) (*x509.CertPool, diag.Diagnostics) {
+	pem := []byte(d.Get("ca_cert").(string))
+	source := "ca_cert"
+
+	if path := d.Get("ca_cert_file").(string); path != "" {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, diag.Errorf("Error reading ca_cert_file %s: %s", path, err)
+		}
+		pem = content
+		source = "ca_cert_file"
+	}
+
+	if len(pem) == 0 {
+		return nil, nil
+	}
+
+	pool, err := newCertPool(pem)
+	if err != nil {
+		return nil, diag.Errorf("Error loading Kuzzle CA certificate from %s: %s", source, err)
+	}
+
+	if insecureSkipVerify {
+		return nil, diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Kuzzle CA certificate ignored",
+			Detail:   source + " is ignored because insecure_skip_verify is enabled.",
+		}}
+	}
+
+	return pool, nil
+}
+
+// checkConnection tests the connection to the Kuzzle server with its health check,
+// or with the configured healthcheck path for HTTP endpoints
+func checkConnection(ctx context.Context, config *Config) error {
+	if config.HealthcheckPath != "" && !isWebSocketEndpoint(config.Endpoint) {
+		return checkHealthcheckPath(ctx, config, config.HealthcheckPath)
+	}
+
+	return checkHealth(ctx, config)
+}
+
+// checkHealth asks Kuzzle for its health with server:healthCheck.
+// Kuzzle versions without this action are probed with server:now, which any version has.
+func checkHealth(ctx context.Context, client KuzzleClient) error {
+	result, err := client.Query(ctx, "server", "healthCheck", nil, nil)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.ID == "api.process.action_not_found") {
+		return checkReachable(ctx, client)
+	}
 	if err != nil {
 		return err
 	}
 
-	if resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusServiceUnavailable {
-		return fmt.Errorf("Kuzzle server is not reachable")
+	var health struct {
+		Status string `json:"status"`
+	}
+	if err := decodeResult(result, &health); err != nil {
+		return err
+	}
+
+	if health.Status != "green" && health.Status != "yellow" {
+		return fmt.Errorf("Kuzzle server is not healthy, status is %q", health.Status)
 	}
 
 	return nil
 }
 
-// checkToken tests the validity of the provided API key
-func checkToken(endpoint string, token string) error {
-	httpClient := &http.Client{}
-	reqBody, _ := json.Marshal(map[string]string{
-		"jwt": token,
-	})
+// checkReachable tests the connection to a Kuzzle server without health check.
+// Any answer from Kuzzle itself, even a refusal to anonymous users, shows that it is reachable.
+func checkReachable(ctx context.Context, client KuzzleClient) error {
+	_, err := client.Query(ctx, "server", "now", nil, nil)
+
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusBadGateway || apiErr.StatusCode == http.StatusServiceUnavailable {
+			return fmt.Errorf("Kuzzle server is not reachable")
+		}
+		return nil
+	}
 
-	resp, err := httpClient.Post(endpoint+"/_checkToken", "application/json", ioutil.NopCloser(bytes.NewReader(reqBody)))
+	return err
+}
+
+// checkHealthcheckPath tests the connection to the Kuzzle server with a custom healthcheck route,
+// answering like the Kuzzle healthcheck route
+func checkHealthcheckPath(ctx context.Context, config *Config, path string) error {
+	resp, err := config.doRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
 		return err
 	}
 
-	defer resp.Body.Close()
-	var jsonBody map[string]interface{}
-	body, _ := ioutil.ReadAll(resp.Body)
+	var health struct {
+		Status string `json:"status"`
+	}
+	if err := unmarshalResult(body, &health); err != nil {
+		return fmt.Errorf("Kuzzle healthcheck %s returned an unexpected response (%d): %s", path, resp.StatusCode, err)
+	}
+
+	if health.Status != "green" && health.Status != "yellow" {
+		return fmt.Errorf("Kuzzle server is not healthy, status is %q", health.Status)
+	}
+
+	return nil
+}
+
+// readyPollInterval is the delay between two connection checks while waiting for Kuzzle to be ready
+var readyPollInterval = 2 * time.Second
+
+// waitUntilReady checks the connection to Kuzzle until it succeeds or the timeout elapses,
+// and returns how long it waited, zero if Kuzzle was ready right away. On failure, the error of the last check is reported.
+func waitUntilReady(ctx context.Context, config *Config, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	deadline, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		err := checkConnection(deadline, config)
+		if err == nil && lastErr == nil {
+			return 0, nil
+		}
+		if err == nil {
+			return time.Since(start), nil
+		}
+
+		// A check interrupted by the deadline says less about Kuzzle than the previous one
+		if lastErr == nil || deadline.Err() == nil {
+			lastErr = err
+		}
+
+		if ctx.Err() != nil {
+			return time.Since(start), ctx.Err()
+		}
+		if config.wait(deadline, readyPollInterval) != nil {
+			if ctx.Err() != nil {
+				return time.Since(start), ctx.Err()
+			}
+			return time.Since(start), fmt.Errorf("Kuzzle is still not ready after %s: %s", timeout, lastErr)
+		}
+	}
+}
+
+// Kinds of tokens given in the provider configuration, as named in error messages
+const (
+	tokenKindAPIKey = "API key"
+	tokenKindJWT    = "JWT"
+)
+
+// checkToken tests the validity of the provided API key or JWT, kind naming it in error messages
+func checkToken(ctx context.Context, config *Config, token string, kind string) error {
+	mode := config.TokenCheckMode
+	if mode == "" || mode == tokenCheckAuto {
+		mode = tokenCheckModeFor(config.serverVersion(ctx))
+	}
+
+	// In header mode, the token is checked by authenticating the check request with it
+	var client KuzzleClient = config
+	var body interface{}
+	switch mode {
+	case tokenCheckHeader:
+		probe := *config
+		probe.Token = token
+		client = &probe
+	default:
+		body = map[string]string{mode: token}
+	}
 
-	if err := json.Unmarshal(body, &jsonBody); err != nil {
+	result, err := client.Query(ctx, "auth", "checkToken", nil, body)
+	if err != nil {
 		return err
 	}
 
-	if jsonBody["result"].(map[string]interface{})["valid"].(bool) != true {
-		return fmt.Errorf("Kuzzle API key is invalid")
+	var validity struct {
+		Valid *bool `json:"valid"`
+	}
+	if err := decodeResult(result, &validity); err != nil {
+		return err
+	}
+
+	if validity.Valid == nil {
+		return fmt.Errorf("Kuzzle token check result has no validity flag")
+	}
+
+	if !*validity.Valid {
+		return fmt.Errorf("Kuzzle %s is invalid", kind)
 	}
 
 	return nil
 }
 
-// tryAuthenticate tries to authenticate with the provided username/password using local strategy
-func tryAuthenticate(endpoint string, username string, password string) (jwt string, err error) {
-	httpClient := &http.Client{}
-	reqBody, _ := json.Marshal(map[string]string{
-		"username": username,
-		"password": password,
-	})
+// tokenCheckModeFor chooses how to send a token to _checkToken depending on the Kuzzle server version.
+// The legacy "jwt" body field is only used for servers positively detected as older than 2.0.0,
+// the "token" one otherwise, including when the version is unknown or cannot be parsed.
+func tokenCheckModeFor(version string) string {
+	if c, err := compareVersions(version, "2.0.0"); err == nil && c < 0 {
+		return tokenCheckJWT
+	}
 
-	resp, err := httpClient.Post(endpoint+"/_login/local", "application/json", ioutil.NopCloser(bytes.NewReader(reqBody)))
+	return tokenCheckToken
+}
+
+// fetchServerVersion returns the version of the Kuzzle server, as reported by server:info
+func fetchServerVersion(ctx context.Context, client KuzzleClient) (string, error) {
+	result, err := client.Query(ctx, "server", "info", nil, nil)
 	if err != nil {
 		return "", err
 	}
 
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("Kuzzle authentication failed")
+	var info struct {
+		ServerInfo struct {
+			Kuzzle struct {
+				Version string `json:"version"`
+			} `json:"kuzzle"`
+		} `json:"serverInfo"`
+	}
+	if err := decodeResult(result, &info); err != nil {
+		return "", err
+	}
+
+	if info.ServerInfo.Kuzzle.Version == "" {
+		return "", fmt.Errorf("Kuzzle server version is unknown")
 	}
 
-	defer resp.Body.Close()
-	var jsonBody map[string]interface{}
-	body, _ := ioutil.ReadAll(resp.Body)
+	return info.ServerInfo.Kuzzle.Version, nil
+}
+
+// loginCredentials returns the body sent to log in: auth_credentials when set,
+// otherwise the username/password pair if complete, nil if there is nothing to log in with
+func loginCredentials(authCredentials string, username string, password string) json.RawMessage {
+	if authCredentials != "" {
+		return json.RawMessage(authCredentials)
+	}
 
-	if err := json.Unmarshal(body, &jsonBody); err != nil {
+	if username != "" && password != "" {
+		body, _ := json.Marshal(map[string]string{"username": username, "password": password})
+		return body
+	}
+
+	return nil
+}
+
+// tryAuthenticate tries to authenticate with the provided username/password using local strategy
+func tryAuthenticate(ctx context.Context, client KuzzleClient, username string, password string) (jwt string, err error) {
+	body, err := json.Marshal(map[string]string{"username": username, "password": password})
+	if err != nil {
+		return "", err
+	}
+
+	return authenticate(ctx, client, "local", body)
+}
+
+// authenticate logs in with the given authentication strategy and returns the obtained token
+func authenticate(ctx context.Context, client KuzzleClient, strategy string, loginBody json.RawMessage) (jwt string, err error) {
+	if !json.Valid(loginBody) {
+		return "", fmt.Errorf("login credentials are not valid JSON")
+	}
+
+	result, err := client.Query(ctx, "auth", "login", map[string]string{"strategy": strategy}, loginBody)
+	if err != nil {
 		return "", err
 	}
 
-	return jsonBody["result"].(map[string]interface{})["jwt"].(string), nil
+	var login struct {
+		JWT string `json:"jwt"`
+	}
+	if err := decodeResult(result, &login); err != nil {
+		return "", err
+	}
+
+	if login.JWT == "" {
+		return "", fmt.Errorf("Kuzzle authentication result has no token")
+	}
+
+	return login.JWT, nil
+}
+
+// logout revokes the token of the configuration
+func logout(ctx context.Context, config *Config) error {
+	return config.query(ctx, http.MethodPost, "/_logout", nil, nil)
+}
+
+// logoutOnTeardown revokes the session opened by the provider once Terraform is done with it
+func (c *Config) logoutOnTeardown() {
+	onTeardown(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		// The token may have been refreshed during the run, there is no point logging in again to revoke it
+		session := *c
+		session.Token = c.authToken()
+		session.credentials = nil
+
+		if err := logout(ctx, &session); err != nil {
+			log.Printf("[WARN] Cannot log out from Kuzzle %s: %s", c.Endpoint, err)
+		}
+	})
+}
+
+// unmarshalResult decodes the "result" part of a Kuzzle response body into result,
+// failing if it is missing or does not have the expected shape
+func unmarshalResult(body []byte, result interface{}) error {
+	var response kuzzleResponse
+	if err := decodeJSON(body, &response); err != nil {
+		return fmt.Errorf("Kuzzle returned an invalid response: %s", err)
+	}
+
+	return decodeResult(response.Result, result)
 }
@@ -0,0 +1,165 @@
+package kuzzle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceKuzzleAPIAction() *schema.Resource {
+	return &schema.Resource{
+		Description: "Executes an arbitrary Kuzzle API action when created, and optionally another one when destroyed, " +
+			"e.g. to call plugin routes the provider does not manage. Changing the action or its arguments executes it again",
+
+		CreateContext: resourceKuzzleAPIActionCreate,
+		ReadContext:   resourceKuzzleAPIActionRead,
+		UpdateContext: resourceKuzzleAPIActionUpdate,
+		DeleteContext: resourceKuzzleAPIActionDelete,
+
+		Schema: map[string]*schema.Schema{
+			"controller": { // Controller of the action
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Controller of the action executed on create, e.g. my-plugin/greeting",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"action": { // Action executed on create
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Action executed on create",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"args": { // Arguments of the action
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arguments of the action executed on create, other than its body, e.g. index or _id",
+			},
+			"body": { // Body of the action
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Description:      "JSON body of the action executed on create",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"destroy_controller": { // Controller of the action executed on destroy
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"destroy_action"},
+				Description:  "Controller of the action executed on destroy, the controller of the create action by default",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"destroy_action": { // Action executed on destroy
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Action executed on destroy, if any. Nothing is executed on destroy otherwise",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"destroy_args": { // Arguments of the action executed on destroy
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				RequiredWith: []string{"destroy_action"},
+				Description:  "Arguments of the action executed on destroy, other than its body",
+			},
+			"destroy_body": { // Body of the action executed on destroy
+				Type:             schema.TypeString,
+				Optional:         true,
+				RequiredWith:     []string{"destroy_action"},
+				Description:      "JSON body of the action executed on destroy",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"response": { // Result of the action executed on create
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON result of the action executed on create, with its keys sorted",
+			},
+		},
+	}
+}
+
+func resourceKuzzleAPIActionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	controller := d.Get("controller").(string)
+	action := d.Get("action").(string)
+
+	request := apiActionRequest(controller, action, d.Get("args").(map[string]interface{}), d.Get("body").(string))
+	var result json.RawMessage
+	if err := config.query(ctx, http.MethodPost, "/_query", request, &result); err != nil {
+		return diag.Errorf("Error executing Kuzzle API action %s:%s: %s", controller, action, err)
+	}
+
+	response, err := normalizeJSON(result)
+	if err != nil {
+		return diag.Errorf("Error executing Kuzzle API action %s:%s: %s", controller, action, err)
+	}
+
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	sum := sha256.Sum256(raw)
+	d.SetId(controller + ":" + action + "/" + hex.EncodeToString(sum[:8]))
+	d.Set("response", response)
+	config.summary.created()
+
+	return nil
+}
+
+// The action is not executed again: its response is the one of the creation, whatever happened since
+func resourceKuzzleAPIActionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+// Only the destroy action can change without executing the create action again, it is kept for later
+func resourceKuzzleAPIActionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+func resourceKuzzleAPIActionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	if action := d.Get("destroy_action").(string); action != "" {
+		controller := d.Get("destroy_controller").(string)
+		if controller == "" {
+			controller = d.Get("controller").(string)
+		}
+
+		request := apiActionRequest(controller, action, d.Get("destroy_args").(map[string]interface{}), d.Get("destroy_body").(string))
+		if err := config.query(ctx, http.MethodPost, "/_query", request, nil); err != nil {
+			return diag.Errorf("Error executing Kuzzle API action %s:%s: %s", controller, action, err)
+		}
+		config.summary.deleted()
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// apiActionRequest returns the request of an API action, as sent to the generic /_query route.
+// The arguments cannot override the controller and action.
+func apiActionRequest(controller string, action string, args map[string]interface{}, body string) map[string]interface{} {
+	request := make(map[string]interface{}, len(args)+3)
+	for name, value := range args {
+		request[name] = value
+	}
+	request["controller"] = controller
+	request["action"] = action
+	if body != "" {
+		request["body"] = json.RawMessage(body)
+	}
+
+	return request
+}
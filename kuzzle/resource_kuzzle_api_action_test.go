@@ -0,0 +1,129 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleAPIActionCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusCode   int
+		response     string
+		wantErr      bool
+		wantResponse string
+	}{
+		{
+			name:         "Success",
+			statusCode:   200,
+			response:     `{"result": {"greeting": "Hello ada", "at": 1}}`,
+			wantErr:      false,
+			wantResponse: `{"at":1,"greeting":"Hello ada"}`,
+		},
+		{
+			name:       "Unknown action",
+			statusCode: 404,
+			response:   `{"error": {"id": "network.http.url_not_found", "message": "API URL not found: my-plugin/greeting:hello"}}`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New("http://kuzzle:7512").
+				Post("/_query").
+				BodyString(`{"action":"hello","body":{"name":"ada"},"controller":"my-plugin/greeting","lang":"en"}`).
+				Reply(tt.statusCode).
+				JSON(json.RawMessage(tt.response))
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleAPIAction().Schema, map[string]interface{}{
+				"controller": "my-plugin/greeting",
+				"action":     "hello",
+				"args":       map[string]interface{}{"lang": "en", "controller": "server"},
+				"body":       `{"name": "ada"}`,
+			})
+			diags := resourceKuzzleAPIActionCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleAPIActionCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if (d.Id() != "") == tt.wantErr {
+				t.Errorf("resourceKuzzleAPIActionCreate() id = %q, wantErr %v", d.Id(), tt.wantErr)
+			}
+			if got := d.Get("response").(string); got != tt.wantResponse {
+				t.Errorf("resourceKuzzleAPIActionCreate() response = %v, want %v", got, tt.wantResponse)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleAPIActionCreate() pending mocks = %v", gock.Pending())
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleAPIActionDelete(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    map[string]interface{}
+		mocked bool
+		body   string
+	}{
+		{
+			name: "No destroy action",
+			raw: map[string]interface{}{
+				"controller": "my-plugin/greeting",
+				"action":     "hello",
+			},
+		},
+		{
+			name: "Create controller by default",
+			raw: map[string]interface{}{
+				"controller":     "my-plugin/greeting",
+				"action":         "hello",
+				"destroy_action": "goodbye",
+				"destroy_args":   map[string]interface{}{"lang": "en"},
+			},
+			mocked: true,
+			body:   `{"action":"goodbye","controller":"my-plugin/greeting","lang":"en"}`,
+		},
+		{
+			name: "Destroy controller",
+			raw: map[string]interface{}{
+				"controller":         "my-plugin/greeting",
+				"action":             "hello",
+				"destroy_controller": "my-plugin/cleanup",
+				"destroy_action":     "purge",
+				"destroy_body":       `{"all": true}`,
+			},
+			mocked: true,
+			body:   `{"action":"purge","body":{"all":true},"controller":"my-plugin/cleanup"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			if tt.mocked {
+				gock.New("http://kuzzle:7512").
+					Post("/_query").
+					BodyString(tt.body).
+					Reply(200).
+					JSON(json.RawMessage(`{"result": {}}`))
+			}
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleAPIAction().Schema, tt.raw)
+			d.SetId("my-plugin/greeting:hello/cafe")
+
+			if diags := resourceKuzzleAPIActionDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+				t.Errorf("resourceKuzzleAPIActionDelete() diags = %v", diags)
+			}
+			if d.Id() != "" {
+				t.Errorf("resourceKuzzleAPIActionDelete() id = %q, want none", d.Id())
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleAPIActionDelete() pending mocks = %v", gock.Pending())
+			}
+		})
+	}
+}
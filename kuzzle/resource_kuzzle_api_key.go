@@ -0,0 +1,201 @@
+package kuzzle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// apiKeySource is the content of a Kuzzle API key, its token being only returned on creation
+type apiKeySource struct {
+	UserID      string `json:"userId"`
+	Description string `json:"description"`
+	ExpiresAt   int64  `json:"expiresAt"`
+	Token       string `json:"token"`
+}
+
+func resourceKuzzleAPIKey() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages a Kuzzle API key of a user, e.g. for a service to authenticate with",
+
+		CreateContext: resourceKuzzleAPIKeyCreate,
+		ReadContext:   resourceKuzzleAPIKeyRead,
+		UpdateContext: resourceKuzzleAPIKeyUpdate,
+		DeleteContext: resourceKuzzleAPIKeyDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzleAPIKeyImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"user_id": { // Owner of the key
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Identifier of the user the API key authenticates as",
+			},
+			"description": { // Key description
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Description of the API key",
+			},
+			"expires_in": { // Key lifetime
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "-1",
+				Description:  "Lifetime of the API key, as a number of milliseconds or a duration such as 30d, -1 for a key that never expires",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"refresh": { // Refresh mode of the writes
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Refresh mode of the API key writes: wait_for or false, the provider refresh mode by default",
+				ValidateFunc: validation.StringInSlice([]string{refreshWaitFor, refreshFalse}, false),
+			},
+			"token": { // Key token
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Token of the API key, only known when the key is created by Terraform",
+			},
+			"expires_at": { // Key expiration date
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Expiration date of the API key, as a Unix timestamp in milliseconds, -1 if it never expires",
+			},
+		},
+	}
+}
+
+func resourceKuzzleAPIKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	userID := d.Get("user_id").(string)
+
+	params := url.Values{}
+	params.Set("expiresIn", d.Get("expires_in").(string))
+	if refresh := config.refreshQuery(d.Get("refresh").(string)); refresh != "" {
+		params.Set("refresh", refreshWaitFor)
+	}
+
+	var key struct {
+		ID     string       `json:"_id"`
+		Source apiKeySource `json:"_source"`
+	}
+	body := map[string]string{"description": d.Get("description").(string)}
+	if err := config.query(ctx, http.MethodPost, apiKeysPath(userID)+"/_create?"+params.Encode(), body, &key); err != nil {
+		return diag.Errorf("Error creating Kuzzle API key of user %q: %s", userID, err)
+	}
+
+	d.SetId(userID + "/" + key.ID)
+	d.Set("token", key.Source.Token)
+	config.summary.created()
+
+	return resourceKuzzleAPIKeyRead(ctx, d, meta)
+}
+
+func resourceKuzzleAPIKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	userID, keyID, err := parseAPIKeyID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var search struct {
+		Hits []struct {
+			ID     string       `json:"_id"`
+			Source apiKeySource `json:"_source"`
+		} `json:"hits"`
+	}
+	body := map[string]interface{}{
+		"query": map[string]interface{}{
+			"ids": map[string]interface{}{"values": []string{keyID}},
+		},
+	}
+	err = config.query(ctx, http.MethodPost, apiKeysPath(userID)+"/_search", body, &search)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle API key %q of user %q: %s", keyID, userID, err)
+	}
+
+	// An expired key can no longer authenticate anyone, it is created again
+	if len(search.Hits) == 0 || apiKeyExpired(search.Hits[0].Source.ExpiresAt, time.Now()) {
+		d.SetId("")
+		return nil
+	}
+
+	key := search.Hits[0].Source
+	d.Set("user_id", userID)
+	d.Set("description", key.Description)
+	d.Set("expires_at", key.ExpiresAt)
+
+	return nil
+}
+
+// API keys cannot be modified, only the refresh mode can be updated, and it only matters for the next write
+func resourceKuzzleAPIKeyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceKuzzleAPIKeyRead(ctx, d, meta)
+}
+
+func resourceKuzzleAPIKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	userID, keyID, err := parseAPIKeyID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	path := apiKeysPath(userID) + "/" + url.PathEscape(keyID) + config.refreshQuery(d.Get("refresh").(string))
+	err = config.query(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil && !isNotFound(err) {
+		return diag.Errorf("Error deleting Kuzzle API key %q of user %q: %s", keyID, userID, err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// The token of an imported key cannot be read back, it stays empty in the state
+func resourceKuzzleAPIKeyImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseAPIKeyID(d.Id()); err != nil {
+		return nil, err
+	}
+
+	// The lifetime of an existing key is unknown, only its expiration date is
+	d.Set("expires_in", "-1")
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// apiKeyExpired tells whether a key expiring at the given Unix timestamp in milliseconds has expired, -1 meaning never
+func apiKeyExpired(expiresAt int64, now time.Time) bool {
+	return expiresAt != -1 && expiresAt <= now.UnixNano()/int64(time.Millisecond)
+}
+
+// apiKeysPath returns the route of the API keys of a user
+func apiKeysPath(userID string) string {
+	return "/users/" + url.PathEscape(userID) + "/api-keys"
+}
+
+// parseAPIKeyID splits an API key resource id into the user identifier and the key identifier.
+// The user identifier can contain slashes, the key identifier cannot.
+func parseAPIKeyID(id string) (userID string, keyID string, err error) {
+	separator := strings.LastIndex(id, "/")
+	if separator <= 0 || separator == len(id)-1 {
+		return "", "", fmt.Errorf("invalid Kuzzle API key id %q, expected <user_id>/<api_key_id>", id)
+	}
+
+	return id[:separator], id[separator+1:], nil
+}
@@ -0,0 +1,320 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleAPIKeyCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		wantErr   bool
+		wantID    string
+		wantToken string
+		mocks     []Mock
+	}{
+		{
+			name:      "Success",
+			version:   "2.10.4",
+			wantErr:   false,
+			wantID:    "ingestion/key-1",
+			wantToken: "kapikey-token",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/users/ingestion/api-keys/_create",
+					response: json.RawMessage(`{"result": {"_id": "key-1", "_source": {
+						"userId": "ingestion", "description": "Ingestion service", "expiresAt": -1, "ttl": -1, "token": "kapikey-token"
+					}}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/users/ingestion/api-keys/_search",
+					response: json.RawMessage(`{"result": {"total": 1, "hits": [{"_id": "key-1", "_source": {
+						"userId": "ingestion", "description": "Ingestion service", "expiresAt": -1, "ttl": -1
+					}}]}}`),
+				},
+			},
+		},
+		{
+			name:    "Unknown user",
+			version: "2.10.4",
+			wantErr: true,
+			wantID:  "",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 404,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/users/ingestion/api-keys/_create",
+					response:   json.RawMessage(`{"error": {"id": "security.user.not_found", "message": "User \"ingestion\" not found."}}`),
+				},
+			},
+		},
+		{
+			name:    "Kuzzle 1",
+			version: "1.11.3",
+			wantErr: true,
+			wantID:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			r := Provider().ResourcesMap["kuzzle_api_key"]
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"user_id":     "ingestion",
+				"description": "Ingestion service",
+			})
+			diags := r.CreateContext(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512", ServerVersion: tt.version})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleAPIKeyCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleAPIKeyCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if got := d.Get("token").(string); got != tt.wantToken {
+				t.Errorf("resourceKuzzleAPIKeyCreate() token = %v, want %v", got, tt.wantToken)
+			}
+			if len(gock.Pending()) != 0 {
+				t.Errorf("resourceKuzzleAPIKeyCreate() pending mocks = %d, want none", len(gock.Pending()))
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleAPIKeyCreateExpiresIn(t *testing.T) {
+	defer gock.Off()
+	gock.
+		New("http://kuzzle:7512").
+		Post("/users/ingestion/api-keys/_create").
+		MatchParam("expiresIn", "^30d$").
+		MatchParam("refresh", refreshWaitFor).
+		BodyString(`{"description":"Ingestion service"}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"_id": "key-1", "_source": {"token": "kapikey-token"}}}`))
+	gock.
+		New("http://kuzzle:7512").
+		Post("/users/ingestion/api-keys/_search").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"total": 1, "hits": [{"_id": "key-1", "_source": {"description": "Ingestion service", "expiresAt": 4102444800000}}]}}`))
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleAPIKey().Schema, map[string]interface{}{
+		"user_id":     "ingestion",
+		"description": "Ingestion service",
+		"expires_in":  "30d",
+	})
+	if diags := resourceKuzzleAPIKeyCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Fatalf("resourceKuzzleAPIKeyCreate() diags = %v", diags)
+	}
+	if got := d.Get("expires_at").(int); got != 4102444800000 {
+		t.Errorf("resourceKuzzleAPIKeyCreate() expires_at = %v, want %v", got, 4102444800000)
+	}
+}
+
+func Test_resourceKuzzleAPIKeyRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		wantID  string
+		mock    Mock
+	}{
+		{
+			name:    "Exists",
+			wantErr: false,
+			wantID:  "ingestion/key-1",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "POST",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ingestion/api-keys/_search",
+				response:   json.RawMessage(`{"result": {"total": 1, "hits": [{"_id": "key-1", "_source": {"description": "Ingestion service", "expiresAt": -1}}]}}`),
+			},
+		},
+		{
+			name:    "Deleted outside of Terraform",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "POST",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ingestion/api-keys/_search",
+				response:   json.RawMessage(`{"result": {"total": 0, "hits": []}}`),
+			},
+		},
+		{
+			name:    "Expired",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "POST",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ingestion/api-keys/_search",
+				response:   json.RawMessage(`{"result": {"total": 1, "hits": [{"_id": "key-1", "_source": {"description": "Ingestion service", "expiresAt": 1617235200000}}]}}`),
+			},
+		},
+		{
+			name:    "User deleted",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "POST",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ingestion/api-keys/_search",
+				response:   json.RawMessage(`{"error": {"id": "security.user.not_found", "message": "User \"ingestion\" not found."}}`),
+			},
+		},
+		{
+			name:    "Connection error",
+			wantErr: true,
+			wantID:  "ingestion/key-1",
+			mock: Mock{
+				enabled: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleAPIKey().Schema, map[string]interface{}{})
+			d.SetId("ingestion/key-1")
+
+			diags := resourceKuzzleAPIKeyRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleAPIKeyRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleAPIKeyRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleAPIKeyDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		mock    Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ingestion/api-keys/key-1",
+				response:   json.RawMessage(`{"result": {"_id": "key-1"}}`),
+			},
+		},
+		{
+			name:    "Already deleted",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ingestion/api-keys/key-1",
+				response:   json.RawMessage(`{"error": {"id": "services.storage.not_found", "message": "Document \"key-1\" not found."}}`),
+			},
+		},
+		{
+			name:    "Forbidden",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 403,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ingestion/api-keys/key-1",
+				response:   json.RawMessage(`{"error": {"message": "Forbidden action [security/deleteApiKey]"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleAPIKey().Schema, map[string]interface{}{})
+			d.SetId("ingestion/key-1")
+
+			diags := resourceKuzzleAPIKeyDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleAPIKeyDelete() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_apiKeyExpired(t *testing.T) {
+	now := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		expiresAt int64
+		want      bool
+	}{
+		{name: "Never expires", expiresAt: -1, want: false},
+		{name: "Expires later", expiresAt: now.Add(time.Hour).UnixNano() / int64(time.Millisecond), want: false},
+		{name: "Expired", expiresAt: now.Add(-time.Hour).UnixNano() / int64(time.Millisecond), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := apiKeyExpired(tt.expiresAt, now); got != tt.want {
+				t.Errorf("apiKeyExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseAPIKeyID(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		wantUserID string
+		wantKeyID  string
+		wantErr    bool
+	}{
+		{name: "Valid", id: "ingestion/key-1", wantUserID: "ingestion", wantKeyID: "key-1"},
+		{name: "Slash in user id", id: "services/ingestion/key-1", wantUserID: "services/ingestion", wantKeyID: "key-1"},
+		{name: "Missing key id", id: "ingestion/", wantErr: true},
+		{name: "No separator", id: "key-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, keyID, err := parseAPIKeyID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAPIKeyID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if userID != tt.wantUserID || keyID != tt.wantKeyID {
+				t.Errorf("parseAPIKeyID() = %q, %q, want %q, %q", userID, keyID, tt.wantUserID, tt.wantKeyID)
+			}
+		})
+	}
+}
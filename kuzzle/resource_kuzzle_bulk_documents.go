@@ -0,0 +1,227 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceKuzzleBulkDocuments() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages a set of documents of a Kuzzle collection as a whole, with batched requests",
+
+		CreateContext: resourceKuzzleBulkDocumentsCreate,
+		ReadContext:   resourceKuzzleBulkDocumentsRead,
+		UpdateContext: resourceKuzzleBulkDocumentsUpdate,
+		DeleteContext: resourceKuzzleBulkDocumentsDelete,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Collection name",
+			},
+			"documents": { // Documents by id
+				Type:             schema.TypeMap,
+				Required:         true,
+				Description:      "JSON body of each document, by document id. Changed and new documents are replaced in place, removed ones are deleted",
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ValidateDiagFunc: validateJSONMap(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"batch_size": { // Number of documents per request
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      200,
+				Description:  "Number of documents sent in each document:mCreateOrReplace, document:mGet or document:mDelete request",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+		},
+	}
+}
+
+func resourceKuzzleBulkDocumentsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	documents, err := expandBulkDocuments(d.Get("documents").(map[string]interface{}))
+	if err != nil {
+		return diag.Errorf("Error parsing the documents of Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	// With rejected documents, the resource is tainted and its documents are deleted when it is replaced
+	d.SetId(index + "/" + collection)
+	if _, diags := createDocuments(ctx, config, index, collection, documents, d.Get("batch_size").(int), true); diags.HasError() {
+		return diags
+	}
+	config.summary.created()
+
+	return resourceKuzzleBulkDocumentsRead(ctx, d, meta)
+}
+
+// Documents deleted outside of Terraform are dropped from the state, so that they are created again,
+// and the fields of the known documents are compared to detect drift
+func resourceKuzzleBulkDocumentsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	known := d.Get("documents").(map[string]interface{})
+
+	sources, err := getDocuments(ctx, config, index, collection, sortedKeys(known), d.Get("batch_size").(int))
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error reading the documents of Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	documents := make(map[string]string, len(sources))
+	for id, source := range sources {
+		delete(source, "_kuzzle_info")
+		body, err := knownFields(known[id].(string), source)
+		if err != nil {
+			return diag.Errorf("Error reading document %q of Kuzzle collection %s/%s: %s", id, index, collection, err)
+		}
+		documents[id] = body
+	}
+
+	d.Set("documents", documents)
+
+	return nil
+}
+
+func resourceKuzzleBulkDocumentsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	batchSize := d.Get("batch_size").(int)
+
+	if !d.HasChange("documents") {
+		return resourceKuzzleBulkDocumentsRead(ctx, d, meta)
+	}
+
+	// The documents of the previous state are kept if the update fails, so that it is planned again
+	d.Partial(true)
+
+	old, new := d.GetChange("documents")
+	oldDocuments := old.(map[string]interface{})
+	newDocuments := new.(map[string]interface{})
+
+	changed := make(map[string]interface{})
+	for id, body := range newDocuments {
+		if previous, ok := oldDocuments[id]; !ok || !equivalentJSON([]byte(previous.(string)), []byte(body.(string))) {
+			changed[id] = body
+		}
+	}
+	var removed []string
+	for _, id := range sortedKeys(oldDocuments) {
+		if _, ok := newDocuments[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+
+	documents, err := expandBulkDocuments(changed)
+	if err != nil {
+		return diag.Errorf("Error parsing the documents of Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+	if _, diags := createDocuments(ctx, config, index, collection, documents, batchSize, true); diags.HasError() {
+		return diags
+	}
+
+	if err := deleteDocuments(ctx, config, index, collection, removed, batchSize); err != nil {
+		return diag.Errorf("Error deleting the documents of Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	d.Partial(false)
+	config.summary.updated()
+
+	return resourceKuzzleBulkDocumentsRead(ctx, d, meta)
+}
+
+func resourceKuzzleBulkDocumentsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	ids := sortedKeys(d.Get("documents").(map[string]interface{}))
+
+	if err := deleteDocuments(ctx, config, index, collection, ids, d.Get("batch_size").(int)); err != nil {
+		return diag.Errorf("Error deleting the documents of Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// expandBulkDocuments converts a map of JSON bodies by id to documents, sorted by id so that batches are stable
+func expandBulkDocuments(raw map[string]interface{}) ([]ndjsonDocument, error) {
+	documents := make([]ndjsonDocument, 0, len(raw))
+	for _, id := range sortedKeys(raw) {
+		body := json.RawMessage(raw[id].(string))
+		if !json.Valid(body) {
+			return nil, fmt.Errorf("document %q: invalid JSON body", id)
+		}
+		documents = append(documents, ndjsonDocument{ID: id, Body: body})
+	}
+
+	return documents, nil
+}
+
+// getDocuments fetches the documents with the given ids with document:mGet requests of batchSize documents,
+// and returns the source of each existing one by id
+func getDocuments(ctx context.Context, config *Config, index string, collection string, ids []string, batchSize int) (map[string]map[string]interface{}, error) {
+	path := "/" + url.PathEscape(index) + "/" + url.PathEscape(collection) + "/_mGet"
+
+	sources := make(map[string]map[string]interface{}, len(ids))
+	for start := 0; start < len(ids); start += batchSize {
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		// The missing documents are listed in the errors of the result, which are of no interest here
+		var result struct {
+			Successes []struct {
+				ID     string                 `json:"_id"`
+				Source map[string]interface{} `json:"_source"`
+			} `json:"successes"`
+		}
+		if err := config.query(ctx, http.MethodPost, path, map[string]interface{}{"ids": ids[start:end]}, &result); err != nil {
+			return nil, err
+		}
+
+		for _, document := range result.Successes {
+			sources[document.ID] = document.Source
+		}
+	}
+
+	return sources, nil
+}
+
+// sortedKeys returns the keys of a map attribute in ascending order
+func sortedKeys(raw map[string]interface{}) []string {
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
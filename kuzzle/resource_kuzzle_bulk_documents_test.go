@@ -0,0 +1,258 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleBulkDocumentsCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		mocks   []Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_mCreateOrReplace",
+					response:   json.RawMessage(`{"result": {"successes": [{"_id": "sensor-1"}, {"_id": "sensor-2"}], "errors": []}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_mGet",
+					response: json.RawMessage(`{"result": {"successes": [
+						{"_id": "sensor-1", "_source": {"name": "lobby", "_kuzzle_info": {"author": "-1"}}},
+						{"_id": "sensor-2", "_source": {"name": "garage", "_kuzzle_info": {"author": "-1"}}}
+					], "errors": []}}`),
+				},
+			},
+		},
+		{
+			name:    "Rejected document",
+			wantErr: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_mCreateOrReplace",
+					response: json.RawMessage(`{"result": {"successes": [{"_id": "sensor-1"}], "errors": [
+						{"document": {"_id": "sensor-2", "body": {"name": "garage"}}, "status": 400, "reason": "Document does not match the collection specifications"}
+					]}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleBulkDocuments().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"documents": map[string]interface{}{
+					"sensor-1": `{"name": "lobby"}`,
+					"sensor-2": `{"name": "garage"}`,
+				},
+			})
+			diags := resourceKuzzleBulkDocumentsCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleBulkDocumentsCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != "iot/sensors" {
+				t.Errorf("resourceKuzzleBulkDocumentsCreate() id = %v, want iot/sensors", d.Id())
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleBulkDocumentsCreate() pending mocks = %v", gock.Pending())
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleBulkDocumentsRead(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantErr       bool
+		wantID        string
+		wantDocuments map[string]interface{}
+		mock          Mock
+	}{
+		{
+			name:    "Drift",
+			wantErr: false,
+			wantID:  "iot/sensors",
+			wantDocuments: map[string]interface{}{
+				"sensor-1": `{"name":"hall"}`,
+			},
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "POST",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mGet",
+				response: json.RawMessage(`{"result": {"successes": [
+					{"_id": "sensor-1", "_source": {"name": "hall", "battery": 87, "_kuzzle_info": {"author": "-1"}}}
+				], "errors": ["sensor-2"]}}`),
+			},
+		},
+		{
+			name:    "Collection deleted",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "POST",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mGet",
+				response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_collection", "message": "Collection \"sensors\" does not exist."}}`),
+			},
+		},
+		{
+			name:    "Connection error",
+			wantErr: true,
+			wantID:  "iot/sensors",
+			mock: Mock{
+				enabled: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleBulkDocuments().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"documents": map[string]interface{}{
+					"sensor-1": `{"name": "lobby"}`,
+					"sensor-2": `{"name": "garage"}`,
+				},
+			})
+			d.SetId("iot/sensors")
+
+			diags := resourceKuzzleBulkDocumentsRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleBulkDocumentsRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleBulkDocumentsRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if tt.wantDocuments != nil {
+				got := d.Get("documents").(map[string]interface{})
+				if len(got) != len(tt.wantDocuments) || got["sensor-1"] != tt.wantDocuments["sensor-1"] {
+					t.Errorf("resourceKuzzleBulkDocumentsRead() documents = %v, want %v", got, tt.wantDocuments)
+				}
+			}
+		})
+	}
+}
+
+// Only the changed and new documents are replaced, and the removed ones deleted
+func Test_resourceKuzzleBulkDocumentsUpdate(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://kuzzle:7512").
+		Put("/iot/sensors/_mCreateOrReplace").
+		BodyString(`{"documents":[{"_id":"sensor-2","body":{"name":"basement"}},{"_id":"sensor-3","body":{"name":"attic"}}]}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"successes": [{"_id": "sensor-2"}, {"_id": "sensor-3"}], "errors": []}}`))
+	gock.New("http://kuzzle:7512").
+		Delete("/iot/sensors/_mDelete").
+		BodyString(`{"ids":["sensor-4"]}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"successes": ["sensor-4"], "errors": []}}`))
+	registerMocks([]Mock{{
+		enabled:    true,
+		statusCode: 200,
+		method:     "POST",
+		url:        "http://kuzzle:7512",
+		route:      "/iot/sensors/_mGet",
+		response: json.RawMessage(`{"result": {"successes": [
+			{"_id": "sensor-1", "_source": {"name": "lobby"}},
+			{"_id": "sensor-2", "_source": {"name": "basement"}},
+			{"_id": "sensor-3", "_source": {"name": "attic"}}
+		], "errors": []}}`),
+	}})
+
+	r := resourceKuzzleBulkDocuments()
+	d := updatedResourceData(t, r, "iot/sensors", map[string]interface{}{
+		"index":      "iot",
+		"collection": "sensors",
+		"documents": map[string]interface{}{
+			"sensor-1": `{"name":"lobby"}`,
+			"sensor-2": `{"name":"garage"}`,
+			"sensor-4": `{"name":"roof"}`,
+		},
+	}, map[string]interface{}{
+		"index":      "iot",
+		"collection": "sensors",
+		"documents": map[string]interface{}{
+			"sensor-1": `{"name": "lobby"}`,
+			"sensor-2": `{"name": "basement"}`,
+			"sensor-3": `{"name": "attic"}`,
+		},
+	})
+
+	if diags := resourceKuzzleBulkDocumentsUpdate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzleBulkDocumentsUpdate() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzleBulkDocumentsUpdate() pending mocks = %v", gock.Pending())
+	}
+	if got := d.Get("documents").(map[string]interface{}); len(got) != 3 {
+		t.Errorf("resourceKuzzleBulkDocumentsUpdate() documents = %v, want 3 documents", got)
+	}
+}
+
+func Test_resourceKuzzleBulkDocumentsDelete(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://kuzzle:7512").
+		Delete("/iot/sensors/_mDelete").
+		BodyString(`{"ids":["sensor-1"]}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"successes": ["sensor-1"], "errors": []}}`))
+	gock.New("http://kuzzle:7512").
+		Delete("/iot/sensors/_mDelete").
+		BodyString(`{"ids":["sensor-2"]}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"successes": ["sensor-2"], "errors": []}}`))
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleBulkDocuments().Schema, map[string]interface{}{
+		"index":      "iot",
+		"collection": "sensors",
+		"batch_size": 1,
+		"documents": map[string]interface{}{
+			"sensor-1": `{"name": "lobby"}`,
+			"sensor-2": `{"name": "garage"}`,
+		},
+	})
+	d.SetId("iot/sensors")
+
+	if diags := resourceKuzzleBulkDocumentsDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzleBulkDocumentsDelete() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzleBulkDocumentsDelete() pending mocks = %v", gock.Pending())
+	}
+	if d.Id() != "" {
+		t.Errorf("resourceKuzzleBulkDocumentsDelete() id = %v, want none", d.Id())
+	}
+}
@@ -0,0 +1,554 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceKuzzleCollection() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages a Kuzzle collection and its mappings",
+
+		CreateContext: resourceKuzzleCollectionCreate,
+		ReadContext:   resourceKuzzleCollectionRead,
+		UpdateContext: resourceKuzzleCollectionUpdate,
+		DeleteContext: resourceKuzzleCollectionDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzleCollectionImportState,
+		},
+
+		CustomizeDiff: resourceKuzzleCollectionCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Collection name",
+			},
+			"mappings": { // Collection mappings
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "{}",
+				Description: "JSON mappings of the collection, e.g. {\"dynamic\": \"strict\", \"properties\": {...}}. New fields are added in place, retyping a field replaces the collection. " +
+					"Fields removed from the configuration, like the ones added by dynamic mappings, are left in the collection",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"dynamic": { // Dynamic policy of the mappings
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "How the collection handles fields missing from its mappings: true, false or strict. Set in the mappings sent to Kuzzle, it cannot be given in mappings as well",
+				ValidateFunc: validation.StringInSlice([]string{"true", "false", "strict"}, false),
+			},
+			"settings": { // Storage settings
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "JSON storage settings of the collection, e.g. {\"number_of_shards\": 1, \"number_of_replicas\": 1}, requiring Kuzzle 2.10.0 or later. " +
+					"Dynamic settings are updated in place, changing a static one such as number_of_shards replaces the collection. Kuzzle does not return them, they are kept as configured",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"default_analyzer": { // Default text analyzer
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "Analyzer of the text fields without one, e.g. standard or french, added to the settings as analysis.analyzer.default on creation. Changing it replaces the collection",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"specifications": { // Validation specifications
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "JSON validation specifications of the collection, e.g. {\"strict\": true, \"fields\": {...}}, applied once the mappings are. " +
+					"If they are rejected when the collection is created, the collection is deleted again",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"dynamic_policy": { // Handling of unmapped fields
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How the collection handles fields missing from its mappings, as reported by Kuzzle: true (added to the mappings), false (stored but not indexed) or strict (rejected)",
+			},
+			"discovered_fields": { // Fields added by dynamic mappings
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Paths of the fields mapped by Kuzzle but missing from the configured mappings, e.g. added by dynamic mappings, sorted alphabetically. Informational only, they never cause updates",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceKuzzleCollectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	mappings, err := collectionMappings(d)
+	if err != nil {
+		return diag.Errorf("Error creating Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	settings, err := collectionSettings(d)
+	if err != nil {
+		return diag.Errorf("Error creating Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	// Settings are only accepted next to the mappings since Kuzzle 2.10.0, which older versions take as mappings
+	var body interface{} = mappings
+	if settings != nil {
+		if err := checkServerVersion(config.ServerVersion, "kuzzle_collection settings", "2.10.0"); err != nil {
+			return diag.FromErr(err)
+		}
+		body = map[string]interface{}{"mappings": mappings, "settings": settings}
+	}
+
+	if err := config.query(ctx, http.MethodPut, collectionPath(index, collection), body, nil); err != nil {
+		return diag.Errorf("Error creating Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	// The collection is only kept if its specifications are accepted as well
+	if specifications := d.Get("specifications").(string); specifications != "" {
+		if err := updateSpecifications(ctx, config, index, collection, specifications); err != nil {
+			rollback := "The collection was deleted."
+			if rollbackErr := config.query(ctx, http.MethodDelete, collectionPath(index, collection), nil, nil); rollbackErr != nil && !isNotFound(rollbackErr) {
+				// The collection is left in the state to be replaced on the next apply
+				d.SetId(index + "/" + collection)
+				rollback = fmt.Sprintf("The collection could not be deleted: %s.", rollbackErr)
+			}
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Error creating Kuzzle collection %s/%s: specifications phase failed", index, collection),
+				Detail:   fmt.Sprintf("The collection was created with its mappings, then its specifications were rejected: %s. %s", err, rollback),
+			}}
+		}
+	}
+
+	d.SetId(index + "/" + collection)
+	config.summary.created()
+
+	return resourceKuzzleCollectionRead(ctx, d, meta)
+}
+
+func resourceKuzzleCollectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index, collection, err := parseCollectionID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var remote map[string]interface{}
+	err = config.query(ctx, http.MethodGet, collectionPath(index, collection)+"/_mapping", nil, &remote)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle collection %s/%s mappings: %s", index, collection, err)
+	}
+
+	// The defaults added by Kuzzle, such as dynamic or _meta, and the fields added by dynamic mappings are not reported as drift
+	mappings, err := knownMappings(d.Get("mappings").(string), remote)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle collection %s/%s mappings: %s", index, collection, err)
+	}
+
+	d.Set("index", index)
+	d.Set("collection", collection)
+	d.Set("mappings", mappings)
+	d.Set("dynamic_policy", dynamicPolicy(remote))
+	if d.Get("dynamic").(string) != "" {
+		d.Set("dynamic", dynamicPolicy(remote))
+	}
+
+	// Specifications are only read when managed, the ones deleted outside of Terraform being planned again
+	if d.Get("specifications").(string) != "" {
+		var specifications struct {
+			Validation json.RawMessage `json:"validation"`
+		}
+		err := config.query(ctx, http.MethodGet, collectionPath(index, collection)+"/_specifications", nil, &specifications)
+		if err != nil && !isNotFound(err) {
+			return diag.Errorf("Error reading Kuzzle collection %s/%s specifications: %s", index, collection, err)
+		}
+		validation := ""
+		if err == nil {
+			if validation, err = normalizeJSON(specifications.Validation); err != nil {
+				return diag.Errorf("Error reading Kuzzle collection %s/%s specifications: %s", index, collection, err)
+			}
+		}
+		d.Set("specifications", validation)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal([]byte(mappings), &state); err != nil {
+		return diag.Errorf("Error reading Kuzzle collection %s/%s mappings: %s", index, collection, err)
+	}
+	discovered := discoveredFields(state["properties"], remote["properties"], "")
+	sort.Strings(discovered)
+	if err := d.Set("discovered_fields", discovered); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// Mappings are merged by Kuzzle: the update only adds new fields, see resourceKuzzleCollectionCustomizeDiff.
+// Only the dynamic settings are updated, the collection being replaced when a static one changes.
+func resourceKuzzleCollectionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	if d.HasChanges("mappings", "dynamic") {
+		mappings, err := collectionMappings(d)
+		if err != nil {
+			return diag.Errorf("Error updating Kuzzle collection %s/%s mappings: %s", index, collection, err)
+		}
+		if err := config.query(ctx, http.MethodPut, collectionPath(index, collection)+"/_mapping", mappings, nil); err != nil {
+			return diag.Errorf("Error updating Kuzzle collection %s/%s mappings: %s", index, collection, err)
+		}
+	}
+	if settings := d.Get("settings").(string); d.HasChange("settings") && settings != "" {
+		if err := updateDynamicSettings(ctx, config, index, collection, settings); err != nil {
+			return diag.Errorf("Error updating Kuzzle collection %s/%s settings: %s", index, collection, err)
+		}
+	}
+
+	// Merged mappings cannot be rolled back: the previous state is kept so that the update is planned again
+	if d.HasChange("specifications") {
+		d.Partial(true)
+		specifications := d.Get("specifications").(string)
+		path := collectionPath(index, collection) + "/_specifications"
+		var err error
+		if specifications == "" {
+			if err = config.query(ctx, http.MethodDelete, path, nil, nil); isNotFound(err) {
+				err = nil
+			}
+		} else {
+			err = updateSpecifications(ctx, config, index, collection, specifications)
+		}
+		if err != nil {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Error updating Kuzzle collection %s/%s: specifications phase failed", index, collection),
+				Detail:   fmt.Sprintf("The mappings and settings of the collection were updated, then its specifications were rejected: %s.", err),
+			}}
+		}
+		d.Partial(false)
+	}
+	config.summary.updated()
+
+	return resourceKuzzleCollectionRead(ctx, d, meta)
+}
+
+// Deleting a collection deletes all its documents, its specifications being deleted first
+func resourceKuzzleCollectionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	if d.Get("specifications").(string) != "" {
+		err := config.query(ctx, http.MethodDelete, collectionPath(index, collection)+"/_specifications", nil, nil)
+		if err != nil && !isNotFound(err) {
+			return diag.Errorf("Error deleting Kuzzle collection %s/%s specifications: %s", index, collection, err)
+		}
+	}
+
+	err := config.query(ctx, http.MethodDelete, collectionPath(index, collection), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return diag.Errorf("Error deleting Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// The whole mappings returned by Kuzzle are imported, they show up as a change until they are set in the configuration
+func resourceKuzzleCollectionImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	index, collection, err := parseCollectionID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("index", index)
+	d.Set("collection", collection)
+	d.Set("mappings", "")
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceKuzzleCollectionCustomizeDiff replaces the collection when its new mappings retype fields,
+// as the storage engine only accepts the addition of fields to existing mappings, or when a static setting changes
+func resourceKuzzleCollectionCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Get("dynamic").(string) != "" {
+		var mappings map[string]interface{}
+		if err := json.Unmarshal([]byte(d.Get("mappings").(string)), &mappings); err == nil {
+			if _, ok := mappings["dynamic"]; ok {
+				return fmt.Errorf("dynamic cannot be set both as an attribute and in mappings")
+			}
+		}
+	}
+
+	if d.Get("default_analyzer").(string) != "" {
+		var settings map[string]interface{}
+		if err := json.Unmarshal([]byte(d.Get("settings").(string)), &settings); err == nil && defaultAnalyzer(settings) != nil {
+			return fmt.Errorf("default_analyzer cannot be set both as an attribute and in settings")
+		}
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	if d.HasChange("mappings") {
+		if reason := incompatibleMappingsChange(d); reason != "" {
+			log.Printf("[INFO] Kuzzle collection %s mappings cannot be updated in place: %s", d.Id(), reason)
+			return d.ForceNew("mappings")
+		}
+	}
+
+	if d.HasChange("settings") {
+		old, new := d.GetChange("settings")
+		if staticSettingsChanged(old.(string), new.(string)) {
+			return d.ForceNew("settings")
+		}
+	}
+
+	return nil
+}
+
+// incompatibleMappingsChange tells why the planned mappings cannot be merged into the current ones,
+// or returns an empty string if they can
+func incompatibleMappingsChange(d *schema.ResourceDiff) string {
+	old, new := d.GetChange("mappings")
+	var oldMappings, newMappings map[string]interface{}
+	if err := json.Unmarshal([]byte(old.(string)), &oldMappings); err != nil {
+		return ""
+	}
+	if err := json.Unmarshal([]byte(new.(string)), &newMappings); err != nil {
+		return ""
+	}
+
+	return incompatibleProperties(oldMappings["properties"], newMappings["properties"], "")
+}
+
+// incompatibleProperties tells why new mapping properties cannot be merged into the old ones,
+// i.e. the field retyped, or returns an empty string if they can.
+// Fields missing from the new properties are left untouched by the merge: they may only exist on the server,
+// e.g. added by dynamic mappings, and never make the properties incompatible.
+func incompatibleProperties(old interface{}, new interface{}, prefix string) string {
+	oldProperties, _ := old.(map[string]interface{})
+	newProperties, _ := new.(map[string]interface{})
+
+	for name, oldField := range oldProperties {
+		newField, ok := newProperties[name]
+		if !ok {
+			continue
+		}
+
+		oldFieldMap, _ := oldField.(map[string]interface{})
+		newFieldMap, _ := newField.(map[string]interface{})
+		if fieldType(oldFieldMap) != fieldType(newFieldMap) {
+			return fmt.Sprintf("field %s%s changes from %s to %s", prefix, name, fieldType(oldFieldMap), fieldType(newFieldMap))
+		}
+
+		if reason := incompatibleProperties(oldFieldMap["properties"], newFieldMap["properties"], prefix+name+"."); reason != "" {
+			return reason
+		}
+	}
+
+	return ""
+}
+
+// knownMappings returns remote mappings restricted to the top level fields of the known mappings,
+// and their properties restricted to the known fields at every nesting level,
+// so that neither the defaults added by Kuzzle nor the fields added by dynamic mappings are reported as drift.
+// Everything is kept when nothing is known, e.g. on import.
+func knownMappings(known string, remote map[string]interface{}) (string, error) {
+	var knownObject map[string]interface{}
+	if known != "" {
+		if err := json.Unmarshal([]byte(known), &knownObject); err != nil {
+			return "", err
+		}
+	}
+
+	if knownProperties, ok := knownObject["properties"].(map[string]interface{}); ok {
+		if remoteProperties, ok := remote["properties"].(map[string]interface{}); ok {
+			restricted := make(map[string]interface{}, len(remote))
+			for field, value := range remote {
+				restricted[field] = value
+			}
+			restricted["properties"] = knownMappingProperties(knownProperties, remoteProperties)
+			remote = restricted
+		}
+	}
+
+	return knownFields(known, remote)
+}
+
+// knownMappingProperties returns the remote properties restricted to the known ones, at every nesting level
+func knownMappingProperties(known map[string]interface{}, remote map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{}, len(known))
+	for name, knownField := range known {
+		remoteField, ok := remote[name]
+		if !ok {
+			continue
+		}
+
+		knownFieldMap, _ := knownField.(map[string]interface{})
+		remoteFieldMap, _ := remoteField.(map[string]interface{})
+		knownSubProperties, knownOK := knownFieldMap["properties"].(map[string]interface{})
+		remoteSubProperties, remoteOK := remoteFieldMap["properties"].(map[string]interface{})
+		if !knownOK || !remoteOK {
+			properties[name] = remoteField
+			continue
+		}
+
+		field := make(map[string]interface{}, len(remoteFieldMap))
+		for key, value := range remoteFieldMap {
+			field[key] = value
+		}
+		field["properties"] = knownMappingProperties(knownSubProperties, remoteSubProperties)
+		properties[name] = field
+	}
+
+	return properties
+}
+
+// collectionMappings returns the mappings to send to Kuzzle, with the dynamic policy set by the dynamic attribute
+func collectionMappings(d *schema.ResourceData) (map[string]interface{}, error) {
+	var mappings map[string]interface{}
+	if err := json.Unmarshal([]byte(d.Get("mappings").(string)), &mappings); err != nil {
+		return nil, err
+	}
+	if mappings == nil {
+		mappings = map[string]interface{}{}
+	}
+	if dynamic := d.Get("dynamic").(string); dynamic != "" {
+		mappings["dynamic"] = dynamic
+	}
+
+	return mappings, nil
+}
+
+// collectionSettings returns the settings to create a collection with, including the default analyzer,
+// or nil if there are none
+func collectionSettings(d *schema.ResourceData) (map[string]interface{}, error) {
+	var settings map[string]interface{}
+	if raw := d.Get("settings").(string); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+			return nil, err
+		}
+	}
+
+	if analyzer := d.Get("default_analyzer").(string); analyzer != "" {
+		if settings == nil {
+			settings = map[string]interface{}{}
+		}
+		analysis, _ := settings["analysis"].(map[string]interface{})
+		if analysis == nil {
+			analysis = map[string]interface{}{}
+			settings["analysis"] = analysis
+		}
+		analyzers, _ := analysis["analyzer"].(map[string]interface{})
+		if analyzers == nil {
+			analyzers = map[string]interface{}{}
+			analysis["analyzer"] = analyzers
+		}
+		analyzers["default"] = map[string]interface{}{"type": analyzer}
+	}
+
+	return settings, nil
+}
+
+// defaultAnalyzer returns the default analyzer defined by storage settings, if any
+func defaultAnalyzer(settings map[string]interface{}) interface{} {
+	analysis, _ := settings["analysis"].(map[string]interface{})
+	analyzers, _ := analysis["analyzer"].(map[string]interface{})
+
+	return analyzers["default"]
+}
+
+// dynamicPolicy returns the dynamic policy of collection mappings, given as a string or a boolean,
+// mappings without one following the storage engine default, true
+func dynamicPolicy(mappings map[string]interface{}) string {
+	switch dynamic := mappings["dynamic"].(type) {
+	case string:
+		return dynamic
+	case bool:
+		return fmt.Sprint(dynamic)
+	default:
+		return "true"
+	}
+}
+
+// discoveredFields returns the paths of the remote mapping fields missing from the known ones.
+// Fields with sub-properties are described by their sub-fields.
+func discoveredFields(known interface{}, remote interface{}, prefix string) []string {
+	knownProperties, _ := known.(map[string]interface{})
+	remoteProperties, _ := remote.(map[string]interface{})
+
+	discovered := []string{}
+	for name, remoteField := range remoteProperties {
+		remoteFieldMap, _ := remoteField.(map[string]interface{})
+		knownField, ok := knownProperties[name]
+		if subProperties, isObject := remoteFieldMap["properties"].(map[string]interface{}); isObject && len(subProperties) > 0 {
+			knownFieldMap, _ := knownField.(map[string]interface{})
+			discovered = append(discovered, discoveredFields(knownFieldMap["properties"], subProperties, prefix+name+".")...)
+			continue
+		}
+		if !ok {
+			discovered = append(discovered, prefix+name)
+		}
+	}
+
+	return discovered
+}
+
+// fieldType returns the type of a mapped field, fields with sub-properties being objects by default
+func fieldType(field map[string]interface{}) string {
+	if t, ok := field["type"].(string); ok {
+		return t
+	}
+
+	return "object"
+}
+
+// collectionPath returns the route of a collection
+func collectionPath(index string, collection string) string {
+	return "/" + url.PathEscape(index) + "/" + url.PathEscape(collection)
+}
+
+// documentPath returns the route of a document
+func documentPath(index string, collection string, documentID string) string {
+	return collectionPath(index, collection) + "/" + url.PathEscape(documentID)
+}
+
+// parseCollectionID splits a collection resource id into the index and collection names
+func parseCollectionID(id string) (index string, collection string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid Kuzzle collection id %q, expected <index>/<collection>", id)
+	}
+
+	return parts[0], parts[1], nil
+}
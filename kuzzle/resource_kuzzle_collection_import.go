@@ -0,0 +1,294 @@
+package kuzzle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ndjsonDocument is a line of the NDJSON files used to import and export documents
+type ndjsonDocument struct {
+	ID   string          `json:"_id,omitempty"`
+	Body json.RawMessage `json:"body"`
+}
+
+// Ways of handling documents whose id already exists in the collection
+const (
+	onConflictFail      = "fail"      // Duplicates are rejected and reported
+	onConflictSkip      = "skip"      // Duplicates are left untouched
+	onConflictOverwrite = "overwrite" // Duplicates are replaced
+)
+
+// importOptions are the settings of an import
+type importOptions struct {
+	batchSize   int    // Number of documents per bulk request
+	contentType string // Content type of the bulk requests
+	onConflict  string // How documents whose id already exists are handled
+}
+
+// bulkItemResult is the outcome of a single bulk:import action
+type bulkItemResult struct {
+	ID     string          `json:"_id"`
+	Status int             `json:"status"`
+	Error  json.RawMessage `json:"error"`
+}
+
+func resourceKuzzleCollectionImport() *schema.Resource {
+	return &schema.Resource{
+		Description: "Loads the documents of a local NDJSON file into a Kuzzle collection",
+
+		CreateContext: resourceKuzzleCollectionImportCreate,
+		ReadContext:   resourceKuzzleCollectionImportRead,
+		UpdateContext: resourceKuzzleCollectionImportUpdate,
+		DeleteContext: resourceKuzzleCollectionImportDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzleCollectionImportImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Collection name",
+			},
+			"path": { // NDJSON file path
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Path of the NDJSON file, one {\"_id\": ..., \"body\": {...}} document per line",
+			},
+			"source_hash": { // Hash of the file, to re-import it when it changes
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Hash of the file content (e.g. filesha256(path)), used to import the file again when it changes",
+			},
+			"batch_size": { // Number of documents per bulk request
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      500,
+				Description:  "Number of documents sent in each bulk request",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"on_conflict": { // Handling of existing document ids
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      onConflictFail,
+				Description:  "How documents whose id already exists in the collection are handled: fail (reporting the conflicting ids), skip or overwrite",
+				ValidateFunc: validation.StringInSlice([]string{onConflictFail, onConflictSkip, onConflictOverwrite}, false),
+			},
+			"bulk_content_type": { // Encoding of the bulk requests
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      contentTypeJSON,
+				Description:  "Content type of the bulk requests: application/json for the Kuzzle bulkData body, or application/x-ndjson for gateways accepting one action or document per line",
+				ValidateFunc: validation.StringInSlice([]string{contentTypeJSON, contentTypeNDJSON}, false),
+			},
+			"imported": { // Number of imported documents
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of imported documents",
+			},
+			"skipped": { // Number of skipped documents
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of documents skipped because their id already existed, with on_conflict set to skip",
+			},
+		},
+	}
+}
+
+func resourceKuzzleCollectionImportCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	path := d.Get("path").(string)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return diag.Errorf("Error opening %s: %s", path, err)
+	}
+	defer file.Close()
+
+	options := importOptions{
+		batchSize:   d.Get("batch_size").(int),
+		contentType: d.Get("bulk_content_type").(string),
+		onConflict:  d.Get("on_conflict").(string),
+	}
+	imported, skipped, err := importDocuments(ctx, config, index, collection, file, options)
+	if err != nil {
+		return diag.Errorf("Error importing %s into Kuzzle collection %s/%s: %s", path, index, collection, err)
+	}
+
+	d.SetId(index + "/" + collection + "/" + path)
+	d.Set("imported", imported)
+	d.Set("skipped", skipped)
+	config.summary.created()
+
+	return resourceKuzzleCollectionImportRead(ctx, d, meta)
+}
+
+// The imported documents are data owned by the collection, there is nothing to refresh
+func resourceKuzzleCollectionImportRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+// Only the import options can be updated, and they only matter for the next import
+func resourceKuzzleCollectionImportUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceKuzzleCollectionImportRead(ctx, d, meta)
+}
+
+// Imported documents are left untouched in the collection when the resource is destroyed
+func resourceKuzzleCollectionImportDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+
+	return nil
+}
+
+// Importing only records a past import of the file, no document is sent to Kuzzle
+func resourceKuzzleCollectionImportImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	index, collection, path, err := parseCollectionImportID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("index", index)
+	d.Set("collection", collection)
+	d.Set("path", path)
+	d.Set("batch_size", 500)
+	d.Set("bulk_content_type", contentTypeJSON)
+	d.Set("on_conflict", onConflictFail)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// parseCollectionImportID splits a collection import resource id into the index, collection and file path.
+// Index and collection names cannot contain slashes, the path can.
+func parseCollectionImportID(id string) (index string, collection string, path string, err error) {
+	parts := strings.SplitN(id, "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid Kuzzle collection import id %q, expected <index>/<collection>/<path>", id)
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
+
+// importDocuments streams the NDJSON documents of r into the collection, with bulk requests of the given options,
+// and returns the number of imported and skipped documents
+func importDocuments(ctx context.Context, config *Config, index string, collection string, r io.Reader, options importOptions) (imported int, skipped int, err error) {
+	reader := bufio.NewReader(r)
+	batch := make([]ndjsonDocument, 0, options.batchSize)
+	line := 0
+
+	for {
+		raw, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return imported, skipped, readErr
+		}
+
+		raw = bytes.TrimSpace(raw)
+		if len(raw) > 0 {
+			line++
+
+			var document ndjsonDocument
+			if err := json.Unmarshal(raw, &document); err != nil {
+				return imported, skipped, fmt.Errorf("line %d is not a valid document: %s", line, err)
+			}
+			batch = append(batch, document)
+		}
+
+		if len(batch) == options.batchSize || (readErr == io.EOF && len(batch) > 0) {
+			batchSkipped, err := bulkImport(ctx, config, index, collection, batch, options)
+			if err != nil {
+				return imported, skipped, err
+			}
+			imported += len(batch) - batchSkipped
+			skipped += batchSkipped
+			batch = batch[:0]
+		}
+
+		if readErr == io.EOF {
+			return imported, skipped, nil
+		}
+	}
+}
+
+// bulkImport writes a batch of documents with a single bulk:import request, and returns the number of skipped documents.
+// The bulk data is sent in a JSON body, or as one action or document per line with the NDJSON content type.
+// Existing documents are replaced with the "index" action when overwriting them, otherwise the "create" action
+// makes Kuzzle reject them with a conflict, reported as an error unless they are skipped.
+func bulkImport(ctx context.Context, config *Config, index string, collection string, documents []ndjsonDocument, options importOptions) (int, error) {
+	actionName := "create"
+	if options.onConflict == onConflictOverwrite {
+		actionName = "index"
+	}
+
+	bulkData := make([]interface{}, 0, 2*len(documents))
+	for _, document := range documents {
+		action := map[string]interface{}{}
+		if document.ID != "" {
+			action["_id"] = document.ID
+		}
+		bulkData = append(bulkData, map[string]interface{}{actionName: action}, document.Body)
+	}
+
+	var body interface{} = map[string]interface{}{"bulkData": bulkData}
+	if options.contentType == contentTypeNDJSON {
+		var lines bytes.Buffer
+		for _, item := range bulkData {
+			line, err := json.Marshal(item)
+			if err != nil {
+				return 0, err
+			}
+			lines.Write(append(line, '\n'))
+		}
+		body = rawBody{contentType: contentTypeNDJSON, data: lines.Bytes()}
+	}
+
+	var result struct {
+		Errors []map[string]bulkItemResult `json:"errors"`
+	}
+	path := "/" + url.PathEscape(index) + "/" + url.PathEscape(collection) + "/_bulk"
+	if err := config.query(ctx, http.MethodPost, path, body, &result); err != nil {
+		return 0, err
+	}
+
+	var conflicts, failures []string
+	for _, item := range result.Errors {
+		for _, outcome := range item {
+			if outcome.Status == http.StatusConflict {
+				conflicts = append(conflicts, outcome.ID)
+				continue
+			}
+			failures = append(failures, fmt.Sprintf("%s (%d): %s", outcome.ID, outcome.Status, outcome.Error))
+		}
+	}
+
+	if len(failures) > 0 {
+		return 0, fmt.Errorf("%d documents were rejected: %s", len(failures), strings.Join(failures, ", "))
+	}
+	if len(conflicts) > 0 && options.onConflict != onConflictSkip {
+		return 0, fmt.Errorf("%d documents already exist: %s", len(conflicts), strings.Join(conflicts, ", "))
+	}
+
+	return len(conflicts), nil
+}
@@ -0,0 +1,299 @@
+package kuzzle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleCollectionImportCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		wantErr      bool
+		wantImported int
+		mocks        []Mock
+	}{
+		{
+			name:         "Imported in two batches",
+			path:         "testdata/documents.ndjson",
+			wantErr:      false,
+			wantImported: 3,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/nyc-open-data/yellow-taxi/_bulk",
+					response:   json.RawMessage(`{"result": {"successes": [{"index": {"_id": "1"}}, {"index": {"_id": "2"}}], "errors": []}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/nyc-open-data/yellow-taxi/_bulk",
+					response:   json.RawMessage(`{"result": {"successes": [{"index": {"_id": "3"}}], "errors": []}}`),
+				},
+			},
+		},
+		{
+			name:    "Rejected document",
+			path:    "testdata/documents.ndjson",
+			wantErr: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/nyc-open-data/yellow-taxi/_bulk",
+					response:   json.RawMessage(`{"result": {"successes": [{"index": {"_id": "1"}}], "errors": [{"index": {"_id": "2", "status": 400, "error": {"reason": "mapper_parsing_exception"}}}]}}`),
+				},
+			},
+		},
+		{
+			name:    "Missing file",
+			path:    "testdata/missing.ndjson",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollectionImport().Schema, map[string]interface{}{
+				"index":      "nyc-open-data",
+				"collection": "yellow-taxi",
+				"path":       tt.path,
+				"batch_size": 2,
+			})
+			diags := resourceKuzzleCollectionImportCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionImportCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+				return
+			}
+			if got := d.Get("imported").(int); got != tt.wantImported {
+				t.Errorf("resourceKuzzleCollectionImportCreate() imported = %v, want %v", got, tt.wantImported)
+			}
+		})
+	}
+}
+
+func Test_collectionExportImportRoundTrip(t *testing.T) {
+	defer gock.Off()
+	config := &Config{Endpoint: "http://kuzzle:7512"}
+
+	gock.
+		New(config.Endpoint).
+		Post("/nyc-open-data/yellow-taxi/_search").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"total": 3, "hits": [
+			{"_id": "1", "_source": {"name": "Ada"}},
+			{"_id": "2", "_source": {"name": "Grace"}},
+			{"_id": "3", "_source": {"name": "Margaret"}}
+		]}}`))
+	gock.
+		New(config.Endpoint).
+		Post("/nyc-open-data/yellow-taxi-copy/_bulk").
+		BodyString(`{"bulkData":[{"create":{"_id":"1"}},{"name":"Ada"},{"create":{"_id":"2"}},{"name":"Grace"},{"create":{"_id":"3"}},{"name":"Margaret"}]}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"successes": [], "errors": []}}`))
+
+	path := filepath.Join(t.TempDir(), "export.ndjson")
+	export := schema.TestResourceDataRaw(t, dataSourceKuzzleCollectionExport().Schema, map[string]interface{}{
+		"index":      "nyc-open-data",
+		"collection": "yellow-taxi",
+		"path":       path,
+	})
+	if diags := dataSourceKuzzleCollectionExportRead(context.Background(), export, config); diags.HasError() {
+		t.Fatalf("dataSourceKuzzleCollectionExportRead() diags = %v", diags)
+	}
+
+	fixture, _ := ioutil.ReadFile("testdata/documents.ndjson")
+	exported, _ := ioutil.ReadFile(path)
+	if string(exported) != string(fixture) {
+		t.Errorf("exported file = %s, want %s", exported, fixture)
+	}
+
+	imported := schema.TestResourceDataRaw(t, resourceKuzzleCollectionImport().Schema, map[string]interface{}{
+		"index":      "nyc-open-data",
+		"collection": "yellow-taxi-copy",
+		"path":       path,
+	})
+	if diags := resourceKuzzleCollectionImportCreate(context.Background(), imported, config); diags.HasError() {
+		t.Fatalf("resourceKuzzleCollectionImportCreate() diags = %v", diags)
+	}
+	if got := imported.Get("imported").(int); got != 3 {
+		t.Errorf("resourceKuzzleCollectionImportCreate() imported = %v, want 3", got)
+	}
+	if !gock.IsDone() {
+		t.Errorf("exported documents were not imported as expected")
+	}
+}
+
+func Test_bulkImportContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		wantBody    string
+	}{
+		{
+			name:        "JSON",
+			contentType: contentTypeJSON,
+			wantBody:    `^\{"bulkData":\[\{"index":\{"_id":"1"\}\},\{"name":"Ada"\}\]\}$`,
+		},
+		{
+			name:        "NDJSON",
+			contentType: contentTypeNDJSON,
+			wantBody:    `^\{"index":\{"_id":"1"\}\}\n\{"name":"Ada"\}\n$`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.
+				New("http://kuzzle:7512").
+				Post("/nyc-open-data/yellow-taxi/_bulk").
+				MatchHeader("Content-Type", "^"+tt.contentType+"$").
+				Filter(func(r *http.Request) bool {
+					// gock only matches the bodies of a few known content types
+					body, _ := ioutil.ReadAll(r.Body)
+					r.Body = ioutil.NopCloser(bytes.NewReader(body))
+					return regexp.MustCompile(tt.wantBody).Match(body)
+				}).
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"successes": [{"index": {"_id": "1"}}], "errors": []}}`))
+
+			documents := []ndjsonDocument{{ID: "1", Body: json.RawMessage(`{"name":"Ada"}`)}}
+			config := &Config{Endpoint: "http://kuzzle:7512"}
+			if _, err := bulkImport(context.Background(), config, "nyc-open-data", "yellow-taxi", documents, importOptions{contentType: tt.contentType, onConflict: onConflictOverwrite}); err != nil {
+				t.Errorf("bulkImport() error = %v", err)
+			}
+		})
+	}
+}
+
+func Test_bulkImportOnConflict(t *testing.T) {
+	conflicts := json.RawMessage(`{"result": {
+		"successes": [{"create": {"_id": "1", "status": 201}}],
+		"errors": [
+			{"create": {"_id": "2", "status": 409, "error": {"message": "Document already exists"}}},
+			{"create": {"_id": "3", "status": 409, "error": {"message": "Document already exists"}}}
+		]
+	}}`)
+
+	tests := []struct {
+		name        string
+		onConflict  string
+		wantAction  string
+		response    json.RawMessage
+		wantSkipped int
+		wantErr     string
+	}{
+		{
+			name:       "Fail",
+			onConflict: onConflictFail,
+			wantAction: "create",
+			response:   conflicts,
+			wantErr:    "2 documents already exist: 2, 3",
+		},
+		{
+			name:        "Skip",
+			onConflict:  onConflictSkip,
+			wantAction:  "create",
+			response:    conflicts,
+			wantSkipped: 2,
+		},
+		{
+			name:       "Overwrite",
+			onConflict: onConflictOverwrite,
+			wantAction: "index",
+			response:   json.RawMessage(`{"result": {"successes": [{"index": {"_id": "1"}}, {"index": {"_id": "2"}}, {"index": {"_id": "3"}}], "errors": []}}`),
+		},
+		{
+			name:       "Skip still reports other errors",
+			onConflict: onConflictSkip,
+			wantAction: "create",
+			response:   json.RawMessage(`{"result": {"successes": [], "errors": [{"create": {"_id": "2", "status": 409}}, {"create": {"_id": "3", "status": 400, "error": "mapper_parsing_exception"}}]}}`),
+			wantErr:    `1 documents were rejected: 3 (400): "mapper_parsing_exception"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.
+				New("http://kuzzle:7512").
+				Post("/nyc-open-data/yellow-taxi/_bulk").
+				BodyString(`^\{"bulkData":\[\{"` + tt.wantAction + `":\{"_id":"1"\}\}`).
+				Reply(200).
+				JSON(tt.response)
+
+			documents := []ndjsonDocument{
+				{ID: "1", Body: json.RawMessage(`{"name":"Ada"}`)},
+				{ID: "2", Body: json.RawMessage(`{"name":"Grace"}`)},
+				{ID: "3", Body: json.RawMessage(`{"name":"Margaret"}`)},
+			}
+			config := &Config{Endpoint: "http://kuzzle:7512"}
+			skipped, err := bulkImport(context.Background(), config, "nyc-open-data", "yellow-taxi", documents, importOptions{contentType: contentTypeJSON, onConflict: tt.onConflict})
+			if (err != nil) != (tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Errorf("bulkImport() error = %v, wantErr %q", err, tt.wantErr)
+				return
+			}
+			if skipped != tt.wantSkipped {
+				t.Errorf("bulkImport() skipped = %v, want %v", skipped, tt.wantSkipped)
+			}
+			if !gock.IsDone() {
+				t.Errorf("bulkImport() did not send the %q action", tt.wantAction)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleCollectionImportImport(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		wantErr        bool
+		wantIndex      string
+		wantCollection string
+		wantPath       string
+	}{
+		{name: "Relative path", id: "iot/sensors/documents.ndjson", wantIndex: "iot", wantCollection: "sensors", wantPath: "documents.ndjson"},
+		{name: "Nested path", id: "iot/sensors/data/2021/documents.ndjson", wantIndex: "iot", wantCollection: "sensors", wantPath: "data/2021/documents.ndjson"},
+		{name: "Absolute path", id: "iot/sensors//tmp/documents.ndjson", wantIndex: "iot", wantCollection: "sensors", wantPath: "/tmp/documents.ndjson"},
+		{name: "Missing path", id: "iot/sensors", wantErr: true},
+		{name: "Empty path", id: "iot/sensors/", wantErr: true},
+		{name: "Missing collection", id: "iot//documents.ndjson", wantErr: true},
+		{name: "Empty", id: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollectionImport().Schema, map[string]interface{}{})
+			d.SetId(tt.id)
+
+			_, err := resourceKuzzleCollectionImportImport(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionImportImport() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if d.Get("index") != tt.wantIndex || d.Get("collection") != tt.wantCollection || d.Get("path") != tt.wantPath {
+				t.Errorf("resourceKuzzleCollectionImportImport() = %v/%v/%v, want %v/%v/%v",
+					d.Get("index"), d.Get("collection"), d.Get("path"), tt.wantIndex, tt.wantCollection, tt.wantPath)
+			}
+		})
+	}
+}
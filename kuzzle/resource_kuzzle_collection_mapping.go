@@ -0,0 +1,145 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceKuzzleCollectionMapping() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages the mappings of an existing Kuzzle collection, without creating or deleting the collection",
+
+		CreateContext: resourceKuzzleCollectionMappingCreate,
+		ReadContext:   resourceKuzzleCollectionMappingRead,
+		UpdateContext: resourceKuzzleCollectionMappingUpdate,
+		DeleteContext: resourceKuzzleCollectionMappingDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzleCollectionMappingImport,
+		},
+
+		CustomizeDiff: resourceKuzzleCollectionMappingCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the existing collection",
+			},
+			"mappings": { // Collection mappings
+				Type:     schema.TypeString,
+				Required: true,
+				Description: "JSON mappings of the collection, e.g. {\"properties\": {...}}. Fields can only be added, retyping one requires the collection to be recreated. " +
+					"Fields removed from the configuration are left in the collection",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+		},
+	}
+}
+
+func resourceKuzzleCollectionMappingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	err := config.query(ctx, http.MethodPut, collectionPath(index, collection)+"/_mapping", json.RawMessage(d.Get("mappings").(string)), nil)
+	if err != nil {
+		return diag.Errorf("Error updating Kuzzle collection %s/%s mappings: %s", index, collection, err)
+	}
+
+	d.SetId(index + "/" + collection)
+	config.summary.created()
+
+	return resourceKuzzleCollectionMappingRead(ctx, d, meta)
+}
+
+func resourceKuzzleCollectionMappingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index, collection, err := parseCollectionID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var remote map[string]interface{}
+	err = config.query(ctx, http.MethodGet, collectionPath(index, collection)+"/_mapping", nil, &remote)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle collection %s/%s mappings: %s", index, collection, err)
+	}
+
+	// The defaults added by Kuzzle, such as dynamic or _meta, and the fields added by dynamic mappings are not reported as drift
+	mappings, err := knownMappings(d.Get("mappings").(string), remote)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle collection %s/%s mappings: %s", index, collection, err)
+	}
+
+	d.Set("index", index)
+	d.Set("collection", collection)
+	d.Set("mappings", mappings)
+
+	return nil
+}
+
+func resourceKuzzleCollectionMappingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	err := config.query(ctx, http.MethodPut, collectionPath(index, collection)+"/_mapping", json.RawMessage(d.Get("mappings").(string)), nil)
+	if err != nil {
+		return diag.Errorf("Error updating Kuzzle collection %s/%s mappings: %s", index, collection, err)
+	}
+	config.summary.updated()
+
+	return resourceKuzzleCollectionMappingRead(ctx, d, meta)
+}
+
+// Mappings cannot be removed from a collection: they are left as they are when the resource is destroyed
+func resourceKuzzleCollectionMappingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+
+	return nil
+}
+
+func resourceKuzzleCollectionMappingImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	index, collection, err := parseCollectionID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("index", index)
+	d.Set("collection", collection)
+	d.Set("mappings", "")
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceKuzzleCollectionMappingCustomizeDiff rejects the mappings that retype fields at plan time:
+// unlike kuzzle_collection, the resource does not own the collection and cannot recreate it
+func resourceKuzzleCollectionMappingCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("mappings") {
+		return nil
+	}
+
+	if reason := incompatibleMappingsChange(d); reason != "" {
+		return fmt.Errorf("Kuzzle collection %s mappings cannot be updated in place, %s: the collection must be recreated by the tool managing it", d.Id(), reason)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleCollectionMappingCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		wantID  string
+		mocks   []Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			wantID:  "iot/sensors",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_mapping",
+					response:   json.RawMessage(`{"result": {"properties": {"name": {"type": "keyword"}}}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_mapping",
+					response:   json.RawMessage(`{"result": {"dynamic": "true", "_meta": {}, "properties": {"name": {"type": "keyword"}}}}`),
+				},
+			},
+		},
+		{
+			name:    "Missing collection",
+			wantErr: true,
+			wantID:  "",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 404,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_mapping",
+					response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_collection", "message": "Collection \"sensors\" does not exist."}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollectionMapping().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"mappings":   `{"properties": {"name": {"type": "keyword"}}}`,
+			})
+			diags := resourceKuzzleCollectionMappingCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionMappingCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleCollectionMappingCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if !tt.wantErr && d.Get("mappings").(string) != `{"properties":{"name":{"type":"keyword"}}}` {
+				t.Errorf("resourceKuzzleCollectionMappingCreate() mappings = %v, want the configured entries only", d.Get("mappings"))
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleCollectionMappingRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		wantID  string
+		mock    Mock
+	}{
+		{
+			name:    "Exists",
+			wantErr: false,
+			wantID:  "iot/sensors",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mapping",
+				response:   json.RawMessage(`{"result": {"properties": {"name": {"type": "keyword"}}}}`),
+			},
+		},
+		{
+			name:    "Collection deleted",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mapping",
+				response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_collection", "message": "Collection \"sensors\" does not exist."}}`),
+			},
+		},
+		{
+			name:    "Connection error",
+			wantErr: true,
+			wantID:  "iot/sensors",
+			mock: Mock{
+				enabled: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollectionMapping().Schema, map[string]interface{}{})
+			d.SetId("iot/sensors")
+
+			diags := resourceKuzzleCollectionMappingRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionMappingRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleCollectionMappingRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleCollectionMappingCustomizeDiff(t *testing.T) {
+	old := map[string]interface{}{
+		"index":      "iot",
+		"collection": "sensors",
+		"mappings":   `{"properties": {"name": {"type": "keyword"}}}`,
+	}
+	tests := []struct {
+		name     string
+		mappings string
+		wantErr  bool
+	}{
+		{name: "Field added", mappings: `{"properties": {"name": {"type": "keyword"}, "temperature": {"type": "float"}}}`, wantErr: false},
+		{name: "Field removed", mappings: `{"properties": {"temperature": {"type": "float"}}}`, wantErr: false},
+		{name: "Field retyped", mappings: `{"properties": {"name": {"type": "text"}}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceKuzzleCollectionMapping()
+			current := schema.TestResourceDataRaw(t, r.Schema, old)
+			current.SetId("iot/sensors")
+
+			_, err := r.Diff(context.Background(), current.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"mappings":   tt.mappings,
+			}), nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Destroying the resource leaves the mappings of the collection untouched
+func Test_resourceKuzzleCollectionMappingDelete(t *testing.T) {
+	defer gock.Off()
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleCollectionMapping().Schema, map[string]interface{}{})
+	d.SetId("iot/sensors")
+
+	if diags := resourceKuzzleCollectionMappingDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzleCollectionMappingDelete() diags = %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("resourceKuzzleCollectionMappingDelete() id = %v, want none", d.Id())
+	}
+}
@@ -0,0 +1,215 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// staticSettings are the storage settings only applied when a collection is created
+var staticSettings = []string{"number_of_shards", "number_of_routing_shards", "routing_partition_size", "codec"}
+
+func resourceKuzzleCollectionSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages a Kuzzle collection created with storage settings, such as its number of shards or its analyzers. " +
+			"Its mappings can be managed with kuzzle_collection_mapping",
+
+		CreateContext: resourceKuzzleCollectionSettingsCreate,
+		ReadContext:   resourceKuzzleCollectionSettingsRead,
+		UpdateContext: resourceKuzzleCollectionSettingsUpdate,
+		DeleteContext: resourceKuzzleCollectionSettingsDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzleCollectionSettingsImport,
+		},
+
+		CustomizeDiff: resourceKuzzleCollectionSettingsCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Collection name",
+			},
+			"settings": { // Storage settings
+				Type:     schema.TypeString,
+				Required: true,
+				Description: "JSON storage settings of the collection, e.g. {\"number_of_shards\": 1, \"number_of_replicas\": 1, \"analysis\": {...}}. " +
+					"Dynamic settings are updated in place, changing a static one such as number_of_shards replaces the collection",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+		},
+	}
+}
+
+func resourceKuzzleCollectionSettingsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	body := map[string]json.RawMessage{"settings": json.RawMessage(d.Get("settings").(string))}
+	if err := config.query(ctx, http.MethodPut, collectionPath(index, collection), body, nil); err != nil {
+		return diag.Errorf("Error creating Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	d.SetId(index + "/" + collection)
+	config.summary.created()
+
+	return resourceKuzzleCollectionSettingsRead(ctx, d, meta)
+}
+
+// Kuzzle does not return the settings of a collection, only its existence is checked
+func resourceKuzzleCollectionSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index, collection, err := parseCollectionID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var exists bool
+	err = config.query(ctx, http.MethodGet, collectionPath(index, collection)+"/_exists", nil, &exists)
+	if err != nil && !isNotFound(err) {
+		return diag.Errorf("Error reading Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+	if !exists {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("index", index)
+	d.Set("collection", collection)
+
+	return nil
+}
+
+// Only the dynamic settings are sent, the collection being replaced when a static one changes.
+// The storage index of the collection is closed while its settings are updated.
+func resourceKuzzleCollectionSettingsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	if err := updateDynamicSettings(ctx, config, index, collection, d.Get("settings").(string)); err != nil {
+		return diag.Errorf("Error updating Kuzzle collection %s/%s settings: %s", index, collection, err)
+	}
+	config.summary.updated()
+
+	return resourceKuzzleCollectionSettingsRead(ctx, d, meta)
+}
+
+// Deleting a collection deletes all its documents
+func resourceKuzzleCollectionSettingsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	err := config.query(ctx, http.MethodDelete, collectionPath(index, collection), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return diag.Errorf("Error deleting Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// The settings of an imported collection are unknown: the configured ones are applied on the next apply,
+// the static ones being left as they are
+func resourceKuzzleCollectionSettingsImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	index, collection, err := parseCollectionID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("index", index)
+	d.Set("collection", collection)
+	d.Set("settings", "")
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceKuzzleCollectionSettingsCustomizeDiff replaces the collection when one of its static settings changes
+func resourceKuzzleCollectionSettingsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("settings") {
+		return nil
+	}
+
+	old, new := d.GetChange("settings")
+	if staticSettingsChanged(old.(string), new.(string)) {
+		return d.ForceNew("settings")
+	}
+
+	return nil
+}
+
+// staticSettingsChanged tells whether a static setting differs between old and new settings.
+// Nothing is reported when the old settings are unknown, e.g. after an import.
+func staticSettingsChanged(old string, new string) bool {
+	if old == "" {
+		return false
+	}
+
+	var oldSettings, newSettings map[string]interface{}
+	if err := json.Unmarshal([]byte(old), &oldSettings); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &newSettings); err != nil {
+		return false
+	}
+
+	for _, name := range staticSettings {
+		// Settings can be given as strings or numbers, e.g. "1" or 1 shard
+		if fmt.Sprint(setting(oldSettings, name)) != fmt.Sprint(setting(newSettings, name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// updateDynamicSettings sends the dynamic storage settings of a collection with collection:update,
+// the static ones being dropped as they can only be applied at creation
+func updateDynamicSettings(ctx context.Context, config *Config, index string, collection string, raw string) error {
+	var settings map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+		return err
+	}
+	for _, name := range staticSettings {
+		delete(settings, name)
+		delete(settings, "index."+name)
+		if nested, ok := settings["index"].(map[string]interface{}); ok {
+			delete(nested, name)
+		}
+	}
+
+	body := map[string]interface{}{"settings": settings}
+	return config.query(ctx, http.MethodPost, collectionPath(index, collection), body, nil)
+}
+
+// setting returns a storage setting, given either as is, prefixed with "index." or nested in an index object
+func setting(settings map[string]interface{}, name string) interface{} {
+	if value, ok := settings[name]; ok {
+		return value
+	}
+	if value, ok := settings["index."+name]; ok {
+		return value
+	}
+	if nested, ok := settings["index"].(map[string]interface{}); ok {
+		return nested[name]
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleCollectionSettingsCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		wantID  string
+		mocks   []Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			wantID:  "iot/sensors",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors",
+					response:   json.RawMessage(`{"result": {"acknowledged": true}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_exists",
+					response:   json.RawMessage(`{"result": true}`),
+				},
+			},
+		},
+		{
+			name:    "Invalid settings",
+			wantErr: true,
+			wantID:  "",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 400,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors",
+					response:   json.RawMessage(`{"error": {"id": "services.storage.invalid_mapping", "message": "failed to parse value [zero] for setting [index.number_of_shards]"}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollectionSettings().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"settings":   `{"number_of_shards": 1, "number_of_replicas": 1}`,
+			})
+			diags := resourceKuzzleCollectionSettingsCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionSettingsCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleCollectionSettingsCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleCollectionSettingsRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		wantID  string
+		mock    Mock
+	}{
+		{
+			name:    "Exists",
+			wantErr: false,
+			wantID:  "iot/sensors",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_exists",
+				response:   json.RawMessage(`{"result": true}`),
+			},
+		},
+		{
+			name:    "Collection deleted",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_exists",
+				response:   json.RawMessage(`{"result": false}`),
+			},
+		},
+		{
+			name:    "Index deleted",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_exists",
+				response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_index", "message": "Index \"iot\" does not exist."}}`),
+			},
+		},
+		{
+			name:    "Connection error",
+			wantErr: true,
+			wantID:  "iot/sensors",
+			mock: Mock{
+				enabled: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollectionSettings().Schema, map[string]interface{}{})
+			d.SetId("iot/sensors")
+
+			diags := resourceKuzzleCollectionSettingsRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionSettingsRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleCollectionSettingsRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
+
+// Static settings are left out of the update, Elasticsearch rejecting them on an existing index
+func Test_resourceKuzzleCollectionSettingsUpdate(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://kuzzle:7512").
+		Post("/iot/sensors").
+		BodyString(`{"settings":{"index":{"refresh_interval":"5s"},"number_of_replicas":2}}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": null}`))
+	registerMocks([]Mock{{
+		enabled:    true,
+		statusCode: 200,
+		method:     "GET",
+		url:        "http://kuzzle:7512",
+		route:      "/iot/sensors/_exists",
+		response:   json.RawMessage(`{"result": true}`),
+	}})
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleCollectionSettings().Schema, map[string]interface{}{
+		"index":      "iot",
+		"collection": "sensors",
+		"settings":   `{"number_of_shards": 1, "number_of_replicas": 2, "index": {"codec": "best_compression", "refresh_interval": "5s"}}`,
+	})
+	d.SetId("iot/sensors")
+
+	if diags := resourceKuzzleCollectionSettingsUpdate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzleCollectionSettingsUpdate() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzleCollectionSettingsUpdate() pending mocks = %v", gock.Pending())
+	}
+}
+
+func Test_resourceKuzzleCollectionSettingsCustomizeDiff(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    string
+		wantReplace bool
+	}{
+		{name: "Dynamic setting", settings: `{"number_of_shards": 2, "number_of_replicas": 3}`, wantReplace: false},
+		{name: "Same static setting as a string", settings: `{"index.number_of_shards": "2", "number_of_replicas": 1}`, wantReplace: false},
+		{name: "Static setting", settings: `{"number_of_shards": 4, "number_of_replicas": 1}`, wantReplace: true},
+		{name: "Static setting added", settings: `{"number_of_shards": 2, "number_of_replicas": 1, "index": {"codec": "best_compression"}}`, wantReplace: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceKuzzleCollectionSettings()
+			current := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"settings":   `{"number_of_shards": 2, "number_of_replicas": 1}`,
+			})
+			current.SetId("iot/sensors")
+
+			diff, err := r.Diff(context.Background(), current.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"settings":   tt.settings,
+			}), nil)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if diff.RequiresNew() != tt.wantReplace {
+				t.Errorf("Diff() RequiresNew = %v, want %v", diff.RequiresNew(), tt.wantReplace)
+			}
+		})
+	}
+}
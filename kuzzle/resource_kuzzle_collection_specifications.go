@@ -0,0 +1,140 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceKuzzleCollectionSpecifications() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages the validation specifications of a Kuzzle collection",
+
+		CreateContext: resourceKuzzleCollectionSpecificationsCreate,
+		ReadContext:   resourceKuzzleCollectionSpecificationsRead,
+		UpdateContext: resourceKuzzleCollectionSpecificationsUpdate,
+		DeleteContext: resourceKuzzleCollectionSpecificationsDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzleCollectionSpecificationsImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Collection name",
+			},
+			"specifications": { // Validation specifications
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "JSON validation specifications of the collection, e.g. {\"strict\": true, \"fields\": {...}, \"validators\": [...]}",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+		},
+	}
+}
+
+func resourceKuzzleCollectionSpecificationsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	if err := updateSpecifications(ctx, config, index, collection, d.Get("specifications").(string)); err != nil {
+		return diag.Errorf("Error creating Kuzzle collection %s/%s specifications: %s", index, collection, err)
+	}
+
+	d.SetId(index + "/" + collection)
+	config.summary.created()
+
+	return resourceKuzzleCollectionSpecificationsRead(ctx, d, meta)
+}
+
+func resourceKuzzleCollectionSpecificationsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index, collection, err := parseCollectionID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var specifications struct {
+		Validation json.RawMessage `json:"validation"`
+	}
+	err = config.query(ctx, http.MethodGet, collectionPath(index, collection)+"/_specifications", nil, &specifications)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle collection %s/%s specifications: %s", index, collection, err)
+	}
+
+	validation, err := normalizeJSON(specifications.Validation)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle collection %s/%s specifications: %s", index, collection, err)
+	}
+
+	d.Set("index", index)
+	d.Set("collection", collection)
+	d.Set("specifications", validation)
+
+	return nil
+}
+
+func resourceKuzzleCollectionSpecificationsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	if err := updateSpecifications(ctx, config, index, collection, d.Get("specifications").(string)); err != nil {
+		return diag.Errorf("Error updating Kuzzle collection %s/%s specifications: %s", index, collection, err)
+	}
+	config.summary.updated()
+
+	return resourceKuzzleCollectionSpecificationsRead(ctx, d, meta)
+}
+
+// Deleting the specifications lets any document into the collection, the collection itself is left untouched
+func resourceKuzzleCollectionSpecificationsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+
+	err := config.query(ctx, http.MethodDelete, collectionPath(index, collection)+"/_specifications", nil, nil)
+	if err != nil && !isNotFound(err) {
+		return diag.Errorf("Error deleting Kuzzle collection %s/%s specifications: %s", index, collection, err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+func resourceKuzzleCollectionSpecificationsImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	index, collection, err := parseCollectionID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("index", index)
+	d.Set("collection", collection)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// updateSpecifications replaces the validation specifications of a collection, with collection:updateSpecifications
+func updateSpecifications(ctx context.Context, config *Config, index string, collection string, specifications string) error {
+	return config.query(ctx, http.MethodPut, collectionPath(index, collection)+"/_specifications", json.RawMessage(specifications), nil)
+}
@@ -0,0 +1,209 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleCollectionSpecificationsCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		wantID  string
+		mocks   []Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			wantID:  "iot/sensors",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_specifications",
+					response:   json.RawMessage(`{"result": {"strict": true, "fields": {"name": {"mandatory": true, "type": "string"}}}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_specifications",
+					response: json.RawMessage(`{"result": {"index": "iot", "collection": "sensors", "validation": {
+						"strict": true, "fields": {"name": {"mandatory": true, "type": "string"}}
+					}}}`),
+				},
+			},
+		},
+		{
+			name:    "Invalid specifications",
+			wantErr: true,
+			wantID:  "",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 400,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_specifications",
+					response:   json.RawMessage(`{"error": {"id": "validation.assert.invalid_specifications", "message": "Field name: the type \"strin\" does not exist."}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollectionSpecifications().Schema, map[string]interface{}{
+				"index":          "iot",
+				"collection":     "sensors",
+				"specifications": `{"strict": true, "fields": {"name": {"mandatory": true, "type": "string"}}}`,
+			})
+			diags := resourceKuzzleCollectionSpecificationsCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionSpecificationsCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleCollectionSpecificationsCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleCollectionSpecificationsRead(t *testing.T) {
+	tests := []struct {
+		name               string
+		wantErr            bool
+		wantID             string
+		wantSpecifications string
+		mock               Mock
+	}{
+		{
+			name:               "Specifications",
+			wantErr:            false,
+			wantID:             "iot/sensors",
+			wantSpecifications: `{"fields":{"name":{"mandatory":true,"type":"string"}},"strict":true}`,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_specifications",
+				response: json.RawMessage(`{"result": {"index": "iot", "collection": "sensors", "validation": {
+					"strict": true, "fields": {"name": {"type": "string", "mandatory": true}}
+				}}}`),
+			},
+		},
+		{
+			name:    "Deleted outside of Terraform",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_specifications",
+				response:   json.RawMessage(`{"error": {"id": "services.storage.not_found", "message": "No specifications defined for index iot and collection sensors"}}`),
+			},
+		},
+		{
+			name:    "Connection error",
+			wantErr: true,
+			wantID:  "iot/sensors",
+			mock: Mock{
+				enabled: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollectionSpecifications().Schema, map[string]interface{}{})
+			d.SetId("iot/sensors")
+
+			diags := resourceKuzzleCollectionSpecificationsRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionSpecificationsRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleCollectionSpecificationsRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if tt.wantSpecifications != "" && d.Get("specifications").(string) != tt.wantSpecifications {
+				t.Errorf("resourceKuzzleCollectionSpecificationsRead() specifications = %v, want %v", d.Get("specifications"), tt.wantSpecifications)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleCollectionSpecificationsDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		mock    Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_specifications",
+				response:   json.RawMessage(`{"result": {"acknowledged": true}}`),
+			},
+		},
+		{
+			name:    "Collection deleted",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_specifications",
+				response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_collection", "message": "Collection \"sensors\" does not exist."}}`),
+			},
+		},
+		{
+			name:    "Forbidden",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 403,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_specifications",
+				response:   json.RawMessage(`{"error": {"message": "Forbidden action [collection/deleteSpecifications]"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollectionSpecifications().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+			})
+			d.SetId("iot/sensors")
+
+			diags := resourceKuzzleCollectionSpecificationsDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionSpecificationsDelete() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+		})
+	}
+}
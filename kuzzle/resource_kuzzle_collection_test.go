@@ -0,0 +1,840 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleCollectionCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		wantID  string
+		mocks   []Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			wantID:  "iot/sensors",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors",
+					response:   json.RawMessage(`{"result": {"acknowledged": true}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_mapping",
+					response:   json.RawMessage(`{"result": {"dynamic": "strict", "_meta": {}, "properties": {"name": {"type": "keyword"}}}}`),
+				},
+			},
+		},
+		{
+			name:    "Unknown index",
+			wantErr: true,
+			wantID:  "",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 404,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors",
+					response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_index", "message": "Index \"iot\" does not exist."}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollection().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"mappings":   `{"dynamic": "strict", "properties": {"name": {"type": "keyword"}}}`,
+			})
+			diags := resourceKuzzleCollectionCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleCollectionCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
+
+// Settings are sent next to the mappings, which requires Kuzzle 2.10.0
+func Test_resourceKuzzleCollectionCreateSettings(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverVersion string
+		wantErr       bool
+	}{
+		{name: "Kuzzle 2.10", serverVersion: "2.10.4", wantErr: false},
+		{name: "Unknown version", serverVersion: "", wantErr: false},
+		{name: "Kuzzle 2.9", serverVersion: "2.9.2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.
+				New("http://kuzzle:7512").
+				Put("/iot/sensors").
+				BodyString(`{"mappings":{"dynamic":"strict","properties":{"name":{"type":"keyword"}}},"settings":{"number_of_replicas":1,"number_of_shards":3}}`).
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"acknowledged": true}}`))
+			gock.
+				New("http://kuzzle:7512").
+				Get("/iot/sensors/_mapping").
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"dynamic": "strict", "properties": {"name": {"type": "keyword"}}}}`))
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollection().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"mappings":   `{"properties": {"name": {"type": "keyword"}}}`,
+				"dynamic":    "strict",
+				"settings":   `{"number_of_replicas": 1, "number_of_shards": 3}`,
+			})
+			diags := resourceKuzzleCollectionCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512", ServerVersion: tt.serverVersion})
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("resourceKuzzleCollectionCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr {
+				if len(gock.Pending()) != 2 {
+					t.Errorf("resourceKuzzleCollectionCreate() sent a request to an unsupported server")
+				}
+				return
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleCollectionCreate() pending mocks = %v", gock.Pending())
+			}
+			if got := d.Get("dynamic").(string); got != "strict" {
+				t.Errorf("resourceKuzzleCollectionCreate() dynamic = %v, want strict", got)
+			}
+		})
+	}
+}
+
+// The default analyzer is translated into the settings sent on creation
+func Test_resourceKuzzleCollectionCreateDefaultAnalyzer(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings string
+		wantBody string
+	}{
+		{
+			name:     "Analyzer only",
+			wantBody: `{"mappings":{"properties":{"name":{"type":"text"}}},"settings":{"analysis":{"analyzer":{"default":{"type":"french"}}}}}`,
+		},
+		{
+			name:     "Analyzer and settings",
+			settings: `{"number_of_shards": 1, "analysis": {"filter": {"elision": {"type": "elision"}}}}`,
+			wantBody: `{"mappings":{"properties":{"name":{"type":"text"}}},"settings":{"analysis":{"analyzer":{"default":{"type":"french"}},"filter":{"elision":{"type":"elision"}}},"number_of_shards":1}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.
+				New("http://kuzzle:7512").
+				Put("/iot/sensors").
+				BodyString(tt.wantBody).
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"acknowledged": true}}`))
+			gock.
+				New("http://kuzzle:7512").
+				Get("/iot/sensors/_mapping").
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"properties": {"name": {"type": "text"}}}}`))
+
+			raw := map[string]interface{}{
+				"index":            "iot",
+				"collection":       "sensors",
+				"mappings":         `{"properties": {"name": {"type": "text"}}}`,
+				"default_analyzer": "french",
+			}
+			if tt.settings != "" {
+				raw["settings"] = tt.settings
+			}
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollection().Schema, raw)
+			if diags := resourceKuzzleCollectionCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+				t.Fatalf("resourceKuzzleCollectionCreate() diags = %v", diags)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleCollectionCreate() pending mocks = %v", gock.Pending())
+			}
+			if got := d.Get("default_analyzer").(string); got != "french" {
+				t.Errorf("resourceKuzzleCollectionCreate() default_analyzer = %v, want french", got)
+			}
+		})
+	}
+}
+
+// The specifications are applied once the collection is created with its mappings, the collection being deleted if they fail
+func Test_resourceKuzzleCollectionCreateSpecifications(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantID      string
+		wantSummary string
+		mocks       []Mock
+	}{
+		{
+			name:   "Success",
+			wantID: "iot/sensors",
+			mocks: []Mock{
+				{enabled: true, statusCode: 200, method: "PUT", url: "http://kuzzle:7512", route: "/iot/sensors$", response: json.RawMessage(`{"result": {"acknowledged": true}}`)},
+				{enabled: true, statusCode: 200, method: "PUT", url: "http://kuzzle:7512", route: "/iot/sensors/_specifications", response: json.RawMessage(`{"result": {}}`)},
+				{enabled: true, statusCode: 200, method: "GET", url: "http://kuzzle:7512", route: "/iot/sensors/_mapping", response: json.RawMessage(`{"result": {"properties": {"name": {"type": "keyword"}}}}`)},
+				{enabled: true, statusCode: 200, method: "GET", url: "http://kuzzle:7512", route: "/iot/sensors/_specifications", response: json.RawMessage(`{"result": {"index": "iot", "collection": "sensors", "validation": {"strict": true}}}`)},
+			},
+		},
+		{
+			name:        "Specifications rolled back",
+			wantID:      "",
+			wantSummary: "Error creating Kuzzle collection iot/sensors: specifications phase failed",
+			mocks: []Mock{
+				{enabled: true, statusCode: 200, method: "PUT", url: "http://kuzzle:7512", route: "/iot/sensors$", response: json.RawMessage(`{"result": {"acknowledged": true}}`)},
+				{enabled: true, statusCode: 400, method: "PUT", url: "http://kuzzle:7512", route: "/iot/sensors/_specifications", response: json.RawMessage(`{"error": {"id": "validation.assert.invalid_specifications", "message": "Invalid specifications."}}`)},
+				{enabled: true, statusCode: 200, method: "DELETE", url: "http://kuzzle:7512", route: "/iot/sensors$", response: json.RawMessage(`{"result": {"acknowledged": true}}`)},
+			},
+		},
+		{
+			name:        "Rollback failed",
+			wantID:      "iot/sensors",
+			wantSummary: "Error creating Kuzzle collection iot/sensors: specifications phase failed",
+			mocks: []Mock{
+				{enabled: true, statusCode: 200, method: "PUT", url: "http://kuzzle:7512", route: "/iot/sensors$", response: json.RawMessage(`{"result": {"acknowledged": true}}`)},
+				{enabled: true, statusCode: 400, method: "PUT", url: "http://kuzzle:7512", route: "/iot/sensors/_specifications", response: json.RawMessage(`{"error": {"id": "validation.assert.invalid_specifications", "message": "Invalid specifications."}}`)},
+				{enabled: true, statusCode: 403, method: "DELETE", url: "http://kuzzle:7512", route: "/iot/sensors$", response: json.RawMessage(`{"error": {"message": "Forbidden action [collection/delete]"}}`)},
+			},
+		},
+		{
+			name:        "Mappings rejected",
+			wantID:      "",
+			wantSummary: "Error creating Kuzzle collection iot/sensors: Kuzzle API error (400): Invalid mappings.",
+			mocks: []Mock{
+				{enabled: true, statusCode: 400, method: "PUT", url: "http://kuzzle:7512", route: "/iot/sensors$", response: json.RawMessage(`{"error": {"message": "Invalid mappings."}}`)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollection().Schema, map[string]interface{}{
+				"index":          "iot",
+				"collection":     "sensors",
+				"mappings":       `{"properties": {"name": {"type": "keyword"}}}`,
+				"specifications": `{"strict": true}`,
+			})
+			diags := resourceKuzzleCollectionCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != (tt.wantSummary != "") {
+				t.Fatalf("resourceKuzzleCollectionCreate() diags = %v, wantSummary %q", diags, tt.wantSummary)
+			}
+			if tt.wantSummary != "" && diags[0].Summary != tt.wantSummary {
+				t.Errorf("resourceKuzzleCollectionCreate() summary = %q, want %q", diags[0].Summary, tt.wantSummary)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleCollectionCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleCollectionCreate() pending mocks = %v", gock.Pending())
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleCollectionRead(t *testing.T) {
+	tests := []struct {
+		name              string
+		mappings          string
+		wantErr           bool
+		wantID            string
+		wantMappings      string
+		wantDynamicPolicy string
+		mock              Mock
+	}{
+		{
+			name:              "Only the configured entries",
+			mappings:          `{"properties": {"name": {"type": "keyword"}}}`,
+			wantErr:           false,
+			wantID:            "iot/sensors",
+			wantMappings:      `{"properties":{"name":{"type":"keyword"}}}`,
+			wantDynamicPolicy: "true",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mapping",
+				response:   json.RawMessage(`{"result": {"dynamic": "true", "_meta": {}, "properties": {"temperature": {"type": "float"}, "name": {"type": "keyword"}}}}`),
+			},
+		},
+		{
+			name:              "Only the configured sub-fields",
+			mappings:          `{"properties": {"location": {"properties": {"lat": {"type": "float"}}}}}`,
+			wantErr:           false,
+			wantID:            "iot/sensors",
+			wantMappings:      `{"properties":{"location":{"properties":{"lat":{"type":"float"}}}}}`,
+			wantDynamicPolicy: "strict",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mapping",
+				response:   json.RawMessage(`{"result": {"dynamic": "strict", "properties": {"location": {"properties": {"lat": {"type": "float"}, "alt": {"type": "long"}}}}}}`),
+			},
+		},
+		{
+			name:              "Everything on import",
+			mappings:          "",
+			wantErr:           false,
+			wantID:            "iot/sensors",
+			wantMappings:      `{"_meta":{},"dynamic":"false","properties":{"name":{"type":"keyword"}}}`,
+			wantDynamicPolicy: "false",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mapping",
+				response:   json.RawMessage(`{"result": {"dynamic": "false", "_meta": {}, "properties": {"name": {"type": "keyword"}}}}`),
+			},
+		},
+		{
+			name:    "Deleted outside of Terraform",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors/_mapping",
+				response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_collection", "message": "Collection \"sensors\" does not exist."}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollection().Schema, map[string]interface{}{})
+			d.SetId("iot/sensors")
+			d.Set("mappings", tt.mappings)
+
+			diags := resourceKuzzleCollectionRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleCollectionRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if tt.wantMappings != "" && d.Get("mappings").(string) != tt.wantMappings {
+				t.Errorf("resourceKuzzleCollectionRead() mappings = %v, want %v", d.Get("mappings"), tt.wantMappings)
+			}
+			if got := d.Get("dynamic_policy").(string); got != tt.wantDynamicPolicy {
+				t.Errorf("resourceKuzzleCollectionRead() dynamic_policy = %v, want %v", got, tt.wantDynamicPolicy)
+			}
+		})
+	}
+}
+
+// A configured dynamic policy is reconciled with the one of the collection, so that a change on the server is reverted
+func Test_resourceKuzzleCollectionReadDynamic(t *testing.T) {
+	defer gock.Off()
+	gock.
+		New("http://kuzzle:7512").
+		Get("/iot/sensors/_mapping").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"dynamic": "true", "properties": {}}}`))
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleCollection().Schema, map[string]interface{}{
+		"index":      "iot",
+		"collection": "sensors",
+		"dynamic":    "strict",
+	})
+	d.SetId("iot/sensors")
+
+	if diags := resourceKuzzleCollectionRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Fatalf("resourceKuzzleCollectionRead() diags = %v", diags)
+	}
+	if got := d.Get("dynamic").(string); got != "true" {
+		t.Errorf("resourceKuzzleCollectionRead() dynamic = %v, want true", got)
+	}
+	if got := d.Get("mappings").(string); got != `{}` {
+		t.Errorf("resourceKuzzleCollectionRead() mappings = %v, want {}", got)
+	}
+}
+
+// Managed specifications deleted outside of Terraform are planned again
+func Test_resourceKuzzleCollectionReadSpecifications(t *testing.T) {
+	tests := []struct {
+		name               string
+		statusCode         int
+		response           string
+		wantSpecifications string
+	}{
+		{name: "Specifications", statusCode: 200, response: `{"result": {"validation": {"strict": true, "fields": {}}}}`, wantSpecifications: `{"fields":{},"strict":true}`},
+		{name: "Deleted specifications", statusCode: 404, response: `{"error": {"message": "Specifications not found"}}`, wantSpecifications: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.
+				New("http://kuzzle:7512").
+				Get("/iot/sensors/_mapping").
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"properties": {}}}`))
+			gock.
+				New("http://kuzzle:7512").
+				Get("/iot/sensors/_specifications").
+				Reply(tt.statusCode).
+				JSON(json.RawMessage(tt.response))
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollection().Schema, map[string]interface{}{
+				"index":          "iot",
+				"collection":     "sensors",
+				"specifications": `{"strict": true, "fields": {}}`,
+			})
+			d.SetId("iot/sensors")
+
+			if diags := resourceKuzzleCollectionRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+				t.Fatalf("resourceKuzzleCollectionRead() diags = %v", diags)
+			}
+			if got := d.Get("specifications").(string); got != tt.wantSpecifications {
+				t.Errorf("resourceKuzzleCollectionRead() specifications = %v, want %v", got, tt.wantSpecifications)
+			}
+		})
+	}
+}
+
+// Fields added by dynamic mappings on the server are discovered, but neither show up in the plan nor replace the collection
+func Test_resourceKuzzleCollectionDynamicFields(t *testing.T) {
+	defer gock.Off()
+	registerMocks([]Mock{
+		{
+			enabled:    true,
+			statusCode: 200,
+			method:     "GET",
+			url:        "http://kuzzle:7512",
+			route:      "/iot/sensors/_mapping",
+			response: json.RawMessage(`{"result": {"dynamic": "true", "properties": {
+				"name": {"type": "keyword"},
+				"temperature": {"type": "float"},
+				"location": {"properties": {"lat": {"type": "float"}, "alt": {"type": "long"}}}
+			}}}`),
+		},
+	})
+
+	r := resourceKuzzleCollection()
+	config := map[string]interface{}{
+		"index":      "iot",
+		"collection": "sensors",
+		"mappings":   `{"dynamic": "true", "properties": {"name": {"type": "keyword"}, "location": {"properties": {"lat": {"type": "float"}}}}}`,
+	}
+	d := schema.TestResourceDataRaw(t, r.Schema, config)
+	d.SetId("iot/sensors")
+
+	if diags := resourceKuzzleCollectionRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Fatalf("resourceKuzzleCollectionRead() diags = %v", diags)
+	}
+	want := []interface{}{"location.alt", "temperature"}
+	if got := d.Get("discovered_fields").([]interface{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("resourceKuzzleCollectionRead() discovered_fields = %v, want %v", got, want)
+	}
+
+	diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Errorf("Diff() = %v, want an empty plan", diff)
+	}
+}
+
+func Test_resourceKuzzleCollectionCustomizeDiff(t *testing.T) {
+	tests := []struct {
+		name            string
+		mappings        string
+		wantRequiresNew bool
+	}{
+		{name: "Field added", mappings: `{"properties": {"name": {"type": "keyword"}, "temperature": {"type": "float"}, "unit": {"type": "keyword"}}}`, wantRequiresNew: false},
+		{name: "Field only in the state", mappings: `{"properties": {"name": {"type": "keyword"}}}`, wantRequiresNew: false},
+		{name: "Field retyped", mappings: `{"properties": {"name": {"type": "text"}, "temperature": {"type": "float"}}}`, wantRequiresNew: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceKuzzleCollection()
+			current := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"mappings":   `{"properties": {"name": {"type": "keyword"}, "temperature": {"type": "float"}}}`,
+			})
+			current.SetId("iot/sensors")
+
+			diff, err := r.Diff(context.Background(), current.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"mappings":   tt.mappings,
+			}), nil)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if diff.RequiresNew() != tt.wantRequiresNew {
+				t.Errorf("Diff() requires new = %v, want %v", diff.RequiresNew(), tt.wantRequiresNew)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleCollectionSettingsDiff(t *testing.T) {
+	tests := []struct {
+		name            string
+		settings        string
+		dynamic         string
+		defaultAnalyzer string
+		mappings        string
+		wantRequiresNew bool
+		wantErr         bool
+	}{
+		{name: "Dynamic setting", settings: `{"number_of_shards": 1, "number_of_replicas": 2}`, mappings: `{}`, wantRequiresNew: false},
+		{name: "Shard count", settings: `{"number_of_shards": 3, "number_of_replicas": 1}`, mappings: `{}`, wantRequiresNew: true},
+		{name: "Dynamic policy", settings: `{"number_of_shards": 1, "number_of_replicas": 1}`, dynamic: "strict", mappings: `{}`, wantRequiresNew: false},
+		{name: "Dynamic policy in mappings too", settings: `{"number_of_shards": 1, "number_of_replicas": 1}`, dynamic: "strict", mappings: `{"dynamic": "false"}`, wantErr: true},
+		{name: "Default analyzer", settings: `{"number_of_shards": 1, "number_of_replicas": 1}`, defaultAnalyzer: "french", mappings: `{}`, wantRequiresNew: true},
+		{name: "Default analyzer in settings too", settings: `{"analysis": {"analyzer": {"default": {"type": "english"}}}}`, defaultAnalyzer: "french", mappings: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceKuzzleCollection()
+			current := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"mappings":   `{}`,
+				"settings":   `{"number_of_shards": 1, "number_of_replicas": 1}`,
+			})
+			current.SetId("iot/sensors")
+
+			raw := map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"mappings":   tt.mappings,
+				"settings":   tt.settings,
+			}
+			if tt.dynamic != "" {
+				raw["dynamic"] = tt.dynamic
+			}
+			if tt.defaultAnalyzer != "" {
+				raw["default_analyzer"] = tt.defaultAnalyzer
+			}
+			diff, err := r.Diff(context.Background(), current.State(), terraform.NewResourceConfigRaw(raw), nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff.RequiresNew() != tt.wantRequiresNew {
+				t.Errorf("Diff() requires new = %v, want %v", diff.RequiresNew(), tt.wantRequiresNew)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleCollectionDynamicValidation(t *testing.T) {
+	for dynamic, wantErr := range map[string]bool{"true": false, "false": false, "strict": false, "runtime": true, "True": true} {
+		diags := resourceKuzzleCollection().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+			"index":      "iot",
+			"collection": "sensors",
+			"dynamic":    dynamic,
+		}))
+		if diags.HasError() != wantErr {
+			t.Errorf("Validate() dynamic %q diags = %v, wantErr %v", dynamic, diags, wantErr)
+		}
+	}
+}
+
+// Only the dynamic settings are updated in place, along with the dynamic policy
+func Test_resourceKuzzleCollectionUpdateSettings(t *testing.T) {
+	defer gock.Off()
+	gock.
+		New("http://kuzzle:7512").
+		Put("/iot/sensors/_mapping").
+		BodyString(`{"dynamic":"false"}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"dynamic": "false"}}`))
+	gock.
+		New("http://kuzzle:7512").
+		Post("/iot/sensors").
+		BodyString(`{"settings":{"number_of_replicas":2}}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"acknowledged": true}}`))
+	gock.
+		New("http://kuzzle:7512").
+		Get("/iot/sensors/_mapping").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"dynamic": "false", "properties": {}}}`))
+
+	r := resourceKuzzleCollection()
+	d := updatedResourceData(t, r, "iot/sensors", map[string]interface{}{
+		"index":      "iot",
+		"collection": "sensors",
+		"dynamic":    "strict",
+		"settings":   `{"number_of_shards": 1, "number_of_replicas": 1}`,
+	}, map[string]interface{}{
+		"index":      "iot",
+		"collection": "sensors",
+		"dynamic":    "false",
+		"settings":   `{"number_of_shards": 1, "number_of_replicas": 2}`,
+	})
+
+	if diags := resourceKuzzleCollectionUpdate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Fatalf("resourceKuzzleCollectionUpdate() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzleCollectionUpdate() pending mocks = %v", gock.Pending())
+	}
+}
+
+func Test_resourceKuzzleCollectionUpdate(t *testing.T) {
+	defer gock.Off()
+	gock.
+		New("http://kuzzle:7512").
+		Put("/iot/sensors/_mapping").
+		JSON(map[string]interface{}{"properties": map[string]interface{}{"name": map[string]interface{}{"type": "keyword"}}}).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"properties": {"name": {"type": "keyword"}}}}`))
+	gock.
+		New("http://kuzzle:7512").
+		Get("/iot/sensors/_mapping").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"dynamic": "true", "properties": {"name": {"type": "keyword"}}}}`))
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleCollection().Schema, map[string]interface{}{
+		"index":      "iot",
+		"collection": "sensors",
+		"mappings":   `{"properties": {"name": {"type": "keyword"}}}`,
+	})
+	d.SetId("iot/sensors")
+
+	if diags := resourceKuzzleCollectionUpdate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Fatalf("resourceKuzzleCollectionUpdate() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzleCollectionUpdate() did not update the mappings in place")
+	}
+}
+
+// Mappings merged before the specifications failed cannot be rolled back, the failed phase is reported
+func Test_resourceKuzzleCollectionUpdateSpecifications(t *testing.T) {
+	defer gock.Off()
+	gock.
+		New("http://kuzzle:7512").
+		Put("/iot/sensors/_mapping").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {}}`))
+	gock.
+		New("http://kuzzle:7512").
+		Put("/iot/sensors/_specifications").
+		BodyString(`{"strict":true}`).
+		Reply(400).
+		JSON(json.RawMessage(`{"error": {"message": "Invalid specifications."}}`))
+
+	r := resourceKuzzleCollection()
+	d := updatedResourceData(t, r, "iot/sensors", map[string]interface{}{
+		"index":          "iot",
+		"collection":     "sensors",
+		"mappings":       `{"properties": {"name": {"type": "keyword"}}}`,
+		"specifications": `{"strict": false}`,
+	}, map[string]interface{}{
+		"index":          "iot",
+		"collection":     "sensors",
+		"mappings":       `{"properties": {"name": {"type": "keyword"}, "unit": {"type": "keyword"}}}`,
+		"specifications": `{"strict": true}`,
+	})
+
+	diags := resourceKuzzleCollectionUpdate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+	if !diags.HasError() {
+		t.Fatalf("resourceKuzzleCollectionUpdate() diags = %v, want an error", diags)
+	}
+	if want := "Error updating Kuzzle collection iot/sensors: specifications phase failed"; diags[0].Summary != want {
+		t.Errorf("resourceKuzzleCollectionUpdate() summary = %q, want %q", diags[0].Summary, want)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzleCollectionUpdate() pending mocks = %v", gock.Pending())
+	}
+}
+
+func Test_resourceKuzzleCollectionDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		mock    Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors",
+				response:   json.RawMessage(`{"result": {"acknowledged": true}}`),
+			},
+		},
+		{
+			name:    "Already deleted",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors",
+				response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_collection", "message": "Collection \"sensors\" does not exist."}}`),
+			},
+		},
+		{
+			name:    "Forbidden",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 403,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/iot/sensors",
+				response:   json.RawMessage(`{"error": {"message": "Forbidden action [collection/delete]"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCollection().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+			})
+			d.SetId("iot/sensors")
+
+			diags := resourceKuzzleCollectionDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCollectionDelete() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_incompatibleProperties(t *testing.T) {
+	old := `{"name": {"type": "keyword"}, "location": {"properties": {"lat": {"type": "float"}}}}`
+	tests := []struct {
+		name       string
+		properties string
+		wantReason string
+	}{
+		{name: "Same", properties: old, wantReason: ""},
+		{name: "Field added", properties: `{"name": {"type": "keyword"}, "location": {"properties": {"lat": {"type": "float"}}}, "temperature": {"type": "float"}}`, wantReason: ""},
+		{name: "Sub-field added", properties: `{"name": {"type": "keyword"}, "location": {"properties": {"lat": {"type": "float"}, "lon": {"type": "float"}}}}`, wantReason: ""},
+		{name: "Explicit object type", properties: `{"name": {"type": "keyword"}, "location": {"type": "object", "properties": {"lat": {"type": "float"}}}}`, wantReason: ""},
+		{name: "Field only on the server", properties: `{"location": {"properties": {"lat": {"type": "float"}}}}`, wantReason: ""},
+		{name: "Sub-field only on the server", properties: `{"name": {"type": "keyword"}, "location": {"properties": {}}}`, wantReason: ""},
+		{name: "Field retyped", properties: `{"name": {"type": "text"}, "location": {"properties": {"lat": {"type": "float"}}}}`, wantReason: "field name changes from keyword to text"},
+		{name: "Sub-field retyped", properties: `{"name": {"type": "keyword"}, "location": {"properties": {"lat": {"type": "double"}}}}`, wantReason: "field location.lat changes from float to double"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var oldProperties, newProperties interface{}
+			if err := json.Unmarshal([]byte(old), &oldProperties); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.properties), &newProperties); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			if got := incompatibleProperties(oldProperties, newProperties, ""); got != tt.wantReason {
+				t.Errorf("incompatibleProperties() = %q, want %q", got, tt.wantReason)
+			}
+		})
+	}
+}
+
+func Test_discoveredFields(t *testing.T) {
+	known := `{"name": {"type": "keyword"}, "location": {"properties": {"lat": {"type": "float"}}}}`
+	tests := []struct {
+		name   string
+		remote string
+		want   []string
+	}{
+		{name: "Nothing new", remote: known, want: []string{}},
+		{name: "Field added", remote: `{"name": {"type": "keyword"}, "unit": {"type": "keyword"}}`, want: []string{"unit"}},
+		{name: "Sub-field added", remote: `{"location": {"properties": {"lat": {"type": "float"}, "lon": {"type": "float"}}}}`, want: []string{"location.lon"}},
+		{name: "Object added", remote: `{"meta": {"properties": {"source": {"type": "keyword"}, "tags": {"type": "keyword"}}}}`, want: []string{"meta.source", "meta.tags"}},
+		{name: "Empty object added", remote: `{"meta": {"type": "object"}}`, want: []string{"meta"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var knownProperties, remoteProperties map[string]interface{}
+			if err := json.Unmarshal([]byte(known), &knownProperties); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.remote), &remoteProperties); err != nil {
+				t.Fatal(err)
+			}
+
+			got := discoveredFields(knownProperties, remoteProperties, "")
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("discoveredFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseCollectionID(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		wantIndex      string
+		wantCollection string
+		wantErr        bool
+	}{
+		{name: "Valid", id: "iot/sensors", wantIndex: "iot", wantCollection: "sensors", wantErr: false},
+		{name: "Missing collection", id: "iot", wantErr: true},
+		{name: "Empty collection", id: "iot/", wantErr: true},
+		{name: "Too many parts", id: "iot/sensors/extra", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, collection, err := parseCollectionID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCollectionID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if index != tt.wantIndex || collection != tt.wantCollection {
+				t.Errorf("parseCollectionID() = %q, %q, want %q, %q", index, collection, tt.wantIndex, tt.wantCollection)
+			}
+		})
+	}
+}
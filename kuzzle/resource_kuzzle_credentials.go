@@ -0,0 +1,180 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceKuzzleCredentials() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages the credentials of a Kuzzle user for an authentication strategy",
+
+		CreateContext: resourceKuzzleCredentialsCreate,
+		ReadContext:   resourceKuzzleCredentialsRead,
+		UpdateContext: resourceKuzzleCredentialsUpdate,
+		DeleteContext: resourceKuzzleCredentialsDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzleCredentialsImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"kuid": { // User identifier
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Kuzzle user identifier",
+			},
+			"strategy": { // Authentication strategy
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Authentication strategy, e.g. local",
+			},
+			"body": { // Credentials of the strategy
+				Type:             schema.TypeString,
+				Required:         true,
+				Sensitive:        true,
+				Description:      "JSON credentials of the strategy, e.g. {\"username\": ..., \"password\": ...}. Secret fields, which Kuzzle never returns, are not checked for drift",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+		},
+	}
+}
+
+func resourceKuzzleCredentialsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	kuid := d.Get("kuid").(string)
+	strategy := d.Get("strategy").(string)
+
+	err := config.query(ctx, http.MethodPost, credentialsPath(strategy, kuid)+"/_create", json.RawMessage(d.Get("body").(string)), nil)
+	if err != nil {
+		return diag.Errorf("Error creating Kuzzle %s credentials of user %q: %s", strategy, kuid, err)
+	}
+
+	d.SetId(kuid + "/" + strategy)
+	config.summary.created()
+
+	return resourceKuzzleCredentialsRead(ctx, d, meta)
+}
+
+func resourceKuzzleCredentialsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	kuid, strategy, err := parseCredentialsID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var remote map[string]interface{}
+	err = config.query(ctx, http.MethodGet, credentialsPath(strategy, kuid), nil, &remote)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle %s credentials of user %q: %s", strategy, kuid, err)
+	}
+
+	body, err := mergeCredentials(d.Get("body").(string), remote)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle %s credentials of user %q: %s", strategy, kuid, err)
+	}
+
+	d.Set("kuid", kuid)
+	d.Set("strategy", strategy)
+	d.Set("body", body)
+
+	return nil
+}
+
+func resourceKuzzleCredentialsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	kuid := d.Get("kuid").(string)
+	strategy := d.Get("strategy").(string)
+
+	err := config.query(ctx, http.MethodPut, credentialsPath(strategy, kuid)+"/_update", json.RawMessage(d.Get("body").(string)), nil)
+	if err != nil {
+		return diag.Errorf("Error updating Kuzzle %s credentials of user %q: %s", strategy, kuid, err)
+	}
+	config.summary.updated()
+
+	return resourceKuzzleCredentialsRead(ctx, d, meta)
+}
+
+func resourceKuzzleCredentialsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	kuid := d.Get("kuid").(string)
+	strategy := d.Get("strategy").(string)
+
+	err := config.query(ctx, http.MethodDelete, credentialsPath(strategy, kuid), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return diag.Errorf("Error deleting Kuzzle %s credentials of user %q: %s", strategy, kuid, err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// The secret fields cannot be read back: they show up as a change of the body until it is set in the configuration
+func resourceKuzzleCredentialsImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	kuid, strategy, err := parseCredentialsID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("kuid", kuid)
+	d.Set("strategy", strategy)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// credentialsPath returns the route of the credentials of a user for a strategy
+func credentialsPath(strategy string, kuid string) string {
+	return "/credentials/" + url.PathEscape(strategy) + "/" + url.PathEscape(kuid)
+}
+
+// parseCredentialsID splits a credentials resource id into the user identifier and the strategy
+func parseCredentialsID(id string) (kuid string, strategy string, err error) {
+	separator := strings.LastIndex(id, "/")
+	if separator <= 0 || separator == len(id)-1 {
+		return "", "", fmt.Errorf("invalid Kuzzle credentials id %q, expected <kuid>/<strategy>", id)
+	}
+
+	return id[:separator], id[separator+1:], nil
+}
+
+// mergeCredentials updates the known credentials with the fields returned by Kuzzle.
+// Secret fields are never returned, so their known value is kept.
+func mergeCredentials(known string, remote map[string]interface{}) (string, error) {
+	merged := map[string]interface{}{}
+	if known != "" {
+		if err := json.Unmarshal([]byte(known), &merged); err != nil {
+			return "", err
+		}
+	}
+
+	for field, value := range remote {
+		// The user identifier is added by Kuzzle, it is not part of the credentials
+		if field == "kuid" {
+			continue
+		}
+		merged[field] = value
+	}
+
+	body, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+
+	return string(body), nil
+}
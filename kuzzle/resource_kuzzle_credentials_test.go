@@ -0,0 +1,214 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleCredentialsCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantErr  bool
+		wantID   string
+		wantBody string
+		mocks    []Mock
+	}{
+		{
+			name:     "Success",
+			wantErr:  false,
+			wantID:   "ada/local",
+			wantBody: `{"password":"s3cr3t","username":"ada"}`,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/credentials/local/ada/_create",
+					response:   json.RawMessage(`{"result": {"username": "ada", "kuid": "ada"}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/credentials/local/ada",
+					response:   json.RawMessage(`{"result": {"username": "ada", "kuid": "ada"}}`),
+				},
+			},
+		},
+		{
+			name:    "Already existing",
+			wantErr: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 400,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/credentials/local/ada/_create",
+					response:   json.RawMessage(`{"error": {"message": "A strategy already exists for user \"ada\"."}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCredentials().Schema, map[string]interface{}{
+				"kuid":     "ada",
+				"strategy": "local",
+				"body":     `{"username": "ada", "password": "s3cr3t"}`,
+			})
+			diags := resourceKuzzleCredentialsCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCredentialsCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+				return
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleCredentialsCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if !tt.wantErr && d.Get("body").(string) != tt.wantBody {
+				t.Errorf("resourceKuzzleCredentialsCreate() body = %v, want %v", d.Get("body"), tt.wantBody)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleCredentialsRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantErr  bool
+		wantID   string
+		wantBody string
+		mock     Mock
+	}{
+		{
+			name:     "Unchanged",
+			wantErr:  false,
+			wantID:   "ada/local",
+			wantBody: `{"password":"s3cr3t","username":"ada"}`,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/credentials/local/ada",
+				response:   json.RawMessage(`{"result": {"username": "ada", "kuid": "ada"}}`),
+			},
+		},
+		{
+			name:     "Username changed outside of Terraform",
+			wantErr:  false,
+			wantID:   "ada/local",
+			wantBody: `{"password":"s3cr3t","username":"lovelace"}`,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				url:        "http://kuzzle:7512",
+				route:      "/credentials/local/ada",
+				response:   json.RawMessage(`{"result": {"username": "lovelace", "kuid": "ada"}}`),
+			},
+		},
+		{
+			name:    "Deleted outside of Terraform",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				url:        "http://kuzzle:7512",
+				route:      "/credentials/local/ada",
+				response:   json.RawMessage(`{"error": {"message": "Credentials not found"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCredentials().Schema, map[string]interface{}{
+				"kuid":     "ada",
+				"strategy": "local",
+				"body":     `{"username": "ada", "password": "s3cr3t"}`,
+			})
+			d.SetId("ada/local")
+
+			diags := resourceKuzzleCredentialsRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleCredentialsRead() diags = %v, wantErr %v", diags, tt.wantErr)
+				return
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleCredentialsRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if tt.wantBody != "" && d.Get("body").(string) != tt.wantBody {
+				t.Errorf("resourceKuzzleCredentialsRead() body = %v, want %v", d.Get("body"), tt.wantBody)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleCredentialsDelete(t *testing.T) {
+	defer gock.Off()
+	gock.
+		New("http://kuzzle:7512").
+		Delete("/credentials/local/ada").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"acknowledged": true}}`))
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleCredentials().Schema, map[string]interface{}{
+		"kuid":     "ada",
+		"strategy": "local",
+		"body":     `{"username": "ada", "password": "s3cr3t"}`,
+	})
+	d.SetId("ada/local")
+
+	if diags := resourceKuzzleCredentialsDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzleCredentialsDelete() diags = %v", diags)
+	}
+	if d.Id() != "" || !gock.IsDone() {
+		t.Errorf("resourceKuzzleCredentialsDelete() did not delete the credentials")
+	}
+}
+
+func Test_resourceKuzzleCredentialsImport(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		wantErr      bool
+		wantKuid     string
+		wantStrategy string
+	}{
+		{name: "Valid", id: "ada/local", wantKuid: "ada", wantStrategy: "local"},
+		{name: "Slash in user id", id: "team/ada/ldap", wantKuid: "team/ada", wantStrategy: "ldap"},
+		{name: "Missing strategy", id: "ada/", wantErr: true},
+		{name: "Missing user id", id: "/local", wantErr: true},
+		{name: "No separator", id: "ada", wantErr: true},
+		{name: "Empty", id: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceKuzzleCredentials().Schema, map[string]interface{}{})
+			d.SetId(tt.id)
+
+			_, err := resourceKuzzleCredentialsImport(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resourceKuzzleCredentialsImport() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := d.Get("kuid").(string); got != tt.wantKuid {
+				t.Errorf("resourceKuzzleCredentialsImport() kuid = %v, want %v", got, tt.wantKuid)
+			}
+			if got := d.Get("strategy").(string); got != tt.wantStrategy {
+				t.Errorf("resourceKuzzleCredentialsImport() strategy = %v, want %v", got, tt.wantStrategy)
+			}
+		})
+	}
+}
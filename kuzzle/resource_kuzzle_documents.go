@@ -0,0 +1,303 @@
+package kuzzle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// documentsCountBatchSize is the number of ids counted by each document:count request
+var documentsCountBatchSize = 1000
+
+// mCreateError is a document rejected by a document:mCreate request
+type mCreateError struct {
+	Document ndjsonDocument `json:"document"`
+	Status   int            `json:"status"`
+	Reason   string         `json:"reason"`
+}
+
+func resourceKuzzleDocuments() *schema.Resource {
+	return &schema.Resource{
+		Description: "Creates a set of documents in a Kuzzle collection with batched requests",
+
+		CreateContext: resourceKuzzleDocumentsCreate,
+		ReadContext:   resourceKuzzleDocumentsRead,
+		UpdateContext: resourceKuzzleDocumentsUpdate,
+		DeleteContext: resourceKuzzleDocumentsDelete,
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Collection name",
+			},
+			"documents": { // Documents to create
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Description:      "JSON array of the documents to create, each one as {\"_id\": ..., \"body\": {...}}, the _id being optional",
+				ValidateDiagFunc: validateJSON(jsonDocuments),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"batch_size": { // Number of documents per request
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      200,
+				Description:  "Number of documents sent in each document:mCreate request",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"upsert": { // Replace existing documents
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Replace the documents whose _id already exists instead of rejecting them, with document:mCreateOrReplace requests, so that creating the resource again converges. Every document must then have an _id",
+			},
+			"document_ids": { // Identifiers of the created documents
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Identifiers of the created documents, generated by Kuzzle for the documents without _id",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceKuzzleDocumentsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	raw := d.Get("documents").(string)
+
+	var documents []ndjsonDocument
+	if err := json.Unmarshal([]byte(raw), &documents); err != nil {
+		return diag.Errorf("Error parsing the documents of Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	upsert := d.Get("upsert").(bool)
+	if upsert {
+		for i, document := range documents {
+			if document.ID == "" {
+				return diag.Errorf("Error creating the documents of Kuzzle collection %s/%s: document %d has no _id, which upsert requires", index, collection, i)
+			}
+		}
+	}
+
+	ids, diags := createDocuments(ctx, config, index, collection, documents, d.Get("batch_size").(int), upsert)
+
+	sum := sha256.Sum256([]byte(raw))
+	d.SetId(index + "/" + collection + "/" + hex.EncodeToString(sum[:8]))
+	if err := d.Set("document_ids", ids); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	// With rejected documents, the created ones are kept in the state of the tainted resource,
+	// so that they are deleted when it is replaced
+	if diags.HasError() {
+		return diags
+	}
+	config.summary.created()
+
+	return resourceKuzzleDocumentsRead(ctx, d, meta)
+}
+
+// Documents are counted rather than fetched, the resource is replaced if some of them are gone
+func resourceKuzzleDocumentsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	ids := expandStrings(d.Get("document_ids").([]interface{}))
+
+	count, err := countDocuments(ctx, config, index, collection, ids)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error counting the documents of Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	if count == 0 && len(ids) > 0 {
+		d.SetId("")
+		return nil
+	}
+
+	// Forgetting the documents makes Terraform create them again
+	if count < len(ids) {
+		log.Printf("[WARN] Only %d of the %d documents created in Kuzzle collection %s/%s still exist", count, len(ids), index, collection)
+		d.Set("documents", "")
+	}
+
+	return nil
+}
+
+// Only the batch size and upsert mode can be updated, and they only matter for the next creation
+func resourceKuzzleDocumentsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceKuzzleDocumentsRead(ctx, d, meta)
+}
+
+func resourceKuzzleDocumentsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	ids := expandStrings(d.Get("document_ids").([]interface{}))
+
+	if err := deleteDocuments(ctx, config, index, collection, ids, d.Get("batch_size").(int)); err != nil {
+		return diag.Errorf("Error deleting the documents of Kuzzle collection %s/%s: %s", index, collection, err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// createDocuments creates the documents with document:mCreate requests of batchSize documents,
+// or document:mCreateOrReplace requests when upserting, and returns the identifiers of the created ones.
+// Each rejected document is reported by a diagnostic giving its position in the documents array.
+func createDocuments(ctx context.Context, config *Config, index string, collection string, documents []ndjsonDocument, batchSize int, upsert bool) ([]string, diag.Diagnostics) {
+	method, action := http.MethodPost, "/_mCreate"
+	if upsert {
+		method, action = http.MethodPut, "/_mCreateOrReplace"
+	}
+	path := "/" + url.PathEscape(index) + "/" + url.PathEscape(collection) + action
+
+	var ids []string
+	var diags diag.Diagnostics
+	for start := 0; start < len(documents); start += batchSize {
+		end := start + batchSize
+		if end > len(documents) {
+			end = len(documents)
+		}
+		batch := documents[start:end]
+
+		var result struct {
+			Successes []struct {
+				ID string `json:"_id"`
+			} `json:"successes"`
+			Errors []mCreateError `json:"errors"`
+		}
+		if err := config.query(ctx, method, path, map[string]interface{}{"documents": batch}, &result); err != nil {
+			return ids, append(diags, diag.Errorf("Error creating documents %d to %d in Kuzzle collection %s/%s: %s", start, end-1, index, collection, err)...)
+		}
+
+		for _, success := range result.Successes {
+			ids = append(ids, success.ID)
+		}
+
+		matched := make([]bool, len(batch))
+		for _, failure := range result.Errors {
+			document := "A document" + describeDocumentID(failure.Document.ID)
+			summary := "Kuzzle rejected a document"
+			if position, ok := rejectedPosition(batch, matched, failure.Document); ok {
+				document = fmt.Sprintf("Document %d%s", start+position, describeDocumentID(failure.Document.ID))
+				summary = fmt.Sprintf("Kuzzle rejected document %d", start+position)
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  summary,
+				Detail:   fmt.Sprintf("%s was not created in collection %s/%s (%d): %s", document, index, collection, failure.Status, failure.Reason),
+			})
+		}
+	}
+
+	return ids, diags
+}
+
+// deleteDocuments deletes the documents with the given ids with document:mDelete requests of batchSize documents
+func deleteDocuments(ctx context.Context, config *Config, index string, collection string, ids []string, batchSize int) error {
+	path := "/" + url.PathEscape(index) + "/" + url.PathEscape(collection) + "/_mDelete"
+	for start := 0; start < len(ids); start += batchSize {
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		// Documents already gone are reported as errors, which are of no interest here
+		err := config.query(ctx, http.MethodDelete, path, map[string]interface{}{"ids": ids[start:end]}, nil)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rejectedPosition finds the position in the batch of a rejected document, by its id or else by its body,
+// among the documents not matched yet
+func rejectedPosition(batch []ndjsonDocument, matched []bool, rejected ndjsonDocument) (int, bool) {
+	for i, document := range batch {
+		if matched[i] || document.ID != rejected.ID {
+			continue
+		}
+
+		if rejected.ID != "" || equivalentJSON(document.Body, rejected.Body) {
+			matched[i] = true
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// describeDocumentID formats a document id for error messages, if it has one
+func describeDocumentID(id string) string {
+	if id == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%q)", id)
+}
+
+// countDocuments returns how many of the documents with the given ids exist in the collection
+func countDocuments(ctx context.Context, config *Config, index string, collection string, ids []string) (int, error) {
+	path := "/" + url.PathEscape(index) + "/" + url.PathEscape(collection) + "/_count"
+
+	total := 0
+	for start := 0; start < len(ids); start += documentsCountBatchSize {
+		end := start + documentsCountBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		body := map[string]interface{}{
+			"query": map[string]interface{}{
+				"ids": map[string]interface{}{"values": ids[start:end]},
+			},
+		}
+		var result struct {
+			Count int `json:"count"`
+		}
+		if err := config.query(ctx, http.MethodPost, path, body, &result); err != nil {
+			return 0, err
+		}
+		total += result.Count
+	}
+
+	return total, nil
+}
+
+// expandStrings converts a list attribute to strings
+func expandStrings(raw []interface{}) []string {
+	values := make([]string, 0, len(raw))
+	for _, value := range raw {
+		values = append(values, value.(string))
+	}
+
+	return values
+}
@@ -0,0 +1,266 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const testDocuments = `[
+	{"_id": "1", "body": {"name": "Ada"}},
+	{"body": {"name": "Grace"}},
+	{"_id": "3", "body": {"name": "Margaret"}}
+]`
+
+func Test_resourceKuzzleDocumentsCreate(t *testing.T) {
+	tests := []struct {
+		name          string
+		responses     []json.RawMessage
+		wantBodies    []string
+		wantIDs       []interface{}
+		wantSummaries []string
+		wantDetails   []string
+	}{
+		{
+			name: "Batches",
+			responses: []json.RawMessage{
+				json.RawMessage(`{"result": {"successes": [{"_id": "1"}, {"_id": "AXn1"}], "errors": []}}`),
+				json.RawMessage(`{"result": {"successes": [{"_id": "3"}], "errors": []}}`),
+				json.RawMessage(`{"result": {"count": 3}}`),
+			},
+			wantBodies: []string{
+				`^\{"documents":\[\{"_id":"1","body":\{"name":"Ada"\}\},\{"body":\{"name":"Grace"\}\}\]\}$`,
+				`^\{"documents":\[\{"_id":"3","body":\{"name":"Margaret"\}\}\]\}$`,
+				`^\{"query":\{"ids":\{"values":\["1","AXn1","3"\]\}\}\}$`,
+			},
+			wantIDs: []interface{}{"1", "AXn1", "3"},
+		},
+		{
+			name: "Partial failure",
+			responses: []json.RawMessage{
+				json.RawMessage(`{"result": {"successes": [{"_id": "1"}], "errors": [
+					{"document": {"body": {"name": "Grace"}}, "status": 400, "reason": "mapper_parsing_exception"}
+				]}}`),
+				json.RawMessage(`{"result": {"successes": [], "errors": [
+					{"document": {"_id": "3", "body": {"name": "Margaret"}}, "status": 409, "reason": "document already exists"}
+				]}}`),
+			},
+			wantBodies: []string{`"_id":"1"`, `"_id":"3"`},
+			wantIDs:    []interface{}{"1"},
+			wantSummaries: []string{
+				"Kuzzle rejected document 1",
+				"Kuzzle rejected document 2",
+			},
+			wantDetails: []string{
+				"Document 1 was not created in collection iot/sensors (400): mapper_parsing_exception",
+				`Document 2 ("3") was not created in collection iot/sensors (409): document already exists`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			routes := []string{"/iot/sensors/_mCreate", "/iot/sensors/_mCreate", "/iot/sensors/_count"}
+			for i, response := range tt.responses {
+				gock.
+					New("http://kuzzle:7512").
+					Post(routes[i]).
+					BodyString(tt.wantBodies[i]).
+					Reply(200).
+					JSON(response)
+			}
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleDocuments().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"documents":  testDocuments,
+				"batch_size": 2,
+			})
+			diags := resourceKuzzleDocumentsCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+
+			var summaries, details []string
+			for _, diagnostic := range diags {
+				summaries = append(summaries, diagnostic.Summary)
+				details = append(details, diagnostic.Detail)
+			}
+			if !reflect.DeepEqual(summaries, tt.wantSummaries) || !reflect.DeepEqual(details, tt.wantDetails) {
+				t.Errorf("resourceKuzzleDocumentsCreate() diags = %v, want %v: %v", diags, tt.wantSummaries, tt.wantDetails)
+			}
+			if got := d.Get("document_ids").([]interface{}); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("resourceKuzzleDocumentsCreate() document_ids = %v, want %v", got, tt.wantIDs)
+			}
+			if d.Id() == "" {
+				t.Errorf("resourceKuzzleDocumentsCreate() did not record the created documents")
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleDocumentsCreate() did not send the expected requests")
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleDocumentsCreateUpsert(t *testing.T) {
+	tests := []struct {
+		name      string
+		documents string
+		wantErr   bool
+	}{
+		{
+			name:      "Documents with ids",
+			documents: `[{"_id": "1", "body": {"name": "Ada"}}, {"_id": "3", "body": {"name": "Margaret"}}]`,
+		},
+		{
+			name:      "Document without id",
+			documents: testDocuments,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New("http://kuzzle:7512").
+				Put("/iot/sensors/_mCreateOrReplace").
+				BodyString(`^\{"documents":\[\{"_id":"1","body":\{"name":"Ada"\}\},\{"_id":"3","body":\{"name":"Margaret"\}\}\]\}$`).
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"successes": [{"_id": "1"}, {"_id": "3"}], "errors": []}}`))
+			gock.New("http://kuzzle:7512").
+				Post("/iot/sensors/_count").
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"count": 2}}`))
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleDocuments().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"documents":  tt.documents,
+				"upsert":     true,
+			})
+			diags := resourceKuzzleDocumentsCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("resourceKuzzleDocumentsCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				if d.Id() != "" || len(gock.Pending()) != 2 {
+					t.Errorf("resourceKuzzleDocumentsCreate() sent documents despite a missing _id")
+				}
+				return
+			}
+			if got := d.Get("document_ids").([]interface{}); !reflect.DeepEqual(got, []interface{}{"1", "3"}) {
+				t.Errorf("resourceKuzzleDocumentsCreate() document_ids = %v, want [1 3]", got)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleDocumentsCreate() did not send the expected requests")
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleDocumentsRead(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		response      json.RawMessage
+		wantErr       bool
+		wantID        string
+		wantDocuments string
+	}{
+		{
+			name:          "All documents exist",
+			statusCode:    200,
+			response:      json.RawMessage(`{"result": {"count": 3}}`),
+			wantID:        "iot/sensors/abc",
+			wantDocuments: testDocuments,
+		},
+		{
+			name:          "Some documents deleted",
+			statusCode:    200,
+			response:      json.RawMessage(`{"result": {"count": 2}}`),
+			wantID:        "iot/sensors/abc",
+			wantDocuments: "",
+		},
+		{
+			name:       "All documents deleted",
+			statusCode: 200,
+			response:   json.RawMessage(`{"result": {"count": 0}}`),
+			wantID:     "",
+		},
+		{
+			name:       "Collection deleted",
+			statusCode: 404,
+			response:   json.RawMessage(`{"error": {"message": "Collection \"sensors\" does not exist"}}`),
+			wantID:     "",
+		},
+		{
+			name:       "Forbidden",
+			statusCode: 403,
+			response:   json.RawMessage(`{"error": {"message": "Forbidden action"}}`),
+			wantErr:    true,
+			wantID:     "iot/sensors/abc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.
+				New("http://kuzzle:7512").
+				Post("/iot/sensors/_count").
+				Reply(tt.statusCode).
+				JSON(tt.response)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleDocuments().Schema, map[string]interface{}{
+				"index":      "iot",
+				"collection": "sensors",
+				"documents":  testDocuments,
+			})
+			d.SetId("iot/sensors/abc")
+			d.Set("document_ids", []string{"1", "AXn1", "3"})
+
+			diags := resourceKuzzleDocumentsRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleDocumentsRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleDocumentsRead() id = %q, want %q", d.Id(), tt.wantID)
+			}
+			if tt.wantID != "" && !tt.wantErr && d.Get("documents").(string) != tt.wantDocuments {
+				t.Errorf("resourceKuzzleDocumentsRead() documents = %q, want %q", d.Get("documents"), tt.wantDocuments)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleDocumentsDelete(t *testing.T) {
+	defer gock.Off()
+	gock.
+		New("http://kuzzle:7512").
+		Delete("/iot/sensors/_mDelete").
+		BodyString(`^\{"ids":\["1","AXn1"\]\}$`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"successes": ["1"], "errors": [{"id": "AXn1", "reason": "document not found"}]}}`))
+	gock.
+		New("http://kuzzle:7512").
+		Delete("/iot/sensors/_mDelete").
+		BodyString(`^\{"ids":\["3"\]\}$`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"successes": ["3"], "errors": []}}`))
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleDocuments().Schema, map[string]interface{}{
+		"index":      "iot",
+		"collection": "sensors",
+		"documents":  testDocuments,
+		"batch_size": 2,
+	})
+	d.SetId("iot/sensors/abc")
+	d.Set("document_ids", []string{"1", "AXn1", "3"})
+
+	if diags := resourceKuzzleDocumentsDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzleDocumentsDelete() diags = %v", diags)
+	}
+	if d.Id() != "" || !gock.IsDone() {
+		t.Errorf("resourceKuzzleDocumentsDelete() did not delete every document")
+	}
+}
@@ -0,0 +1,120 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceKuzzleFirstAdmin() *schema.Resource {
+	return &schema.Resource{
+		Description: "Creates the first administrator of a new Kuzzle stack, which anonymous users can do as long as no administrator exists. " +
+			"Once anonymous rights are reset, the provider must authenticate with an administrator account",
+
+		CreateContext: resourceKuzzleFirstAdminCreate,
+		ReadContext:   resourceKuzzleFirstAdminRead,
+		DeleteContext: resourceKuzzleFirstAdminDelete,
+
+		Schema: map[string]*schema.Schema{
+			"kuid": { // User identifier
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Kuzzle user identifier of the administrator",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"content": { // User content
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "{}",
+				Description:      "JSON content of the administrator, e.g. {\"fullName\": \"...\"}",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"username": { // Local strategy username
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Username of the administrator local credentials",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"password": { // Local strategy password
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Sensitive:    true,
+				Description:  "Password of the administrator local credentials",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"reset": { // Restrict the default roles
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Reset the anonymous and default roles so that they no longer grant every API action",
+			},
+		},
+	}
+}
+
+func resourceKuzzleFirstAdminCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	kuid := d.Get("kuid").(string)
+
+	body := map[string]interface{}{
+		"content": json.RawMessage(d.Get("content").(string)),
+		"credentials": map[string]interface{}{
+			localStrategy: map[string]string{
+				"username": d.Get("username").(string),
+				"password": d.Get("password").(string),
+			},
+		},
+	}
+	path := "/_createFirstAdmin/" + url.PathEscape(kuid)
+	if d.Get("reset").(bool) {
+		path += "?reset=true"
+	}
+	if err := config.query(ctx, http.MethodPost, path, body, nil); err != nil {
+		return diag.Errorf("Error creating Kuzzle first administrator %q: %s", kuid, err)
+	}
+
+	d.SetId(kuid)
+	config.summary.created()
+
+	return resourceKuzzleFirstAdminRead(ctx, d, meta)
+}
+
+// The administrator account is not read, as anonymous rights may have been reset:
+// the resource is created again only if no administrator exists anymore, e.g. after the storage is wiped
+func resourceKuzzleFirstAdminRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	var admin struct {
+		Exists bool `json:"exists"`
+	}
+	if err := config.query(ctx, http.MethodGet, "/_adminExists", nil, &admin); err != nil {
+		return diag.Errorf("Error checking if a Kuzzle administrator exists: %s", err)
+	}
+	if !admin.Exists {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("kuid", d.Id())
+
+	return nil
+}
+
+// The administrator is left in place when the resource is destroyed, so that the stack cannot be left without one.
+// It can be managed afterwards with kuzzle_user.
+func resourceKuzzleFirstAdminDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleFirstAdminCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		reset   bool
+		wantErr bool
+		wantID  string
+		mocks   []Mock
+	}{
+		{
+			name:    "Success",
+			reset:   true,
+			wantErr: false,
+			wantID:  "admin",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/_createFirstAdmin/admin",
+					response:   json.RawMessage(`{"result": {"_id": "admin", "_source": {"profileIds": ["admin"]}}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/_adminExists",
+					response:   json.RawMessage(`{"result": {"exists": true}}`),
+				},
+			},
+		},
+		{
+			name:    "Administrator already exists",
+			reset:   false,
+			wantErr: true,
+			wantID:  "",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 400,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/_createFirstAdmin/admin",
+					response:   json.RawMessage(`{"error": {"id": "api.process.admin_exists", "message": "Admin user is already set."}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleFirstAdmin().Schema, map[string]interface{}{
+				"kuid":     "admin",
+				"username": "admin",
+				"password": "s3cr3t",
+				"reset":    tt.reset,
+			})
+			diags := resourceKuzzleFirstAdminCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleFirstAdminCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleFirstAdminCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleFirstAdminCreate() pending mocks = %v", gock.Pending())
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleFirstAdminCreateBody(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://kuzzle:7512").
+		Post("/_createFirstAdmin/admin").
+		MatchParam("reset", "true").
+		BodyString(`{"content":{"fullName":"Ada"},"credentials":{"local":{"password":"s3cr3t","username":"ada"}}}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"_id": "admin"}}`))
+	gock.New("http://kuzzle:7512").
+		Get("/_adminExists").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"exists": true}}`))
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleFirstAdmin().Schema, map[string]interface{}{
+		"kuid":     "admin",
+		"content":  `{"fullName": "Ada"}`,
+		"username": "ada",
+		"password": "s3cr3t",
+		"reset":    true,
+	})
+	if diags := resourceKuzzleFirstAdminCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzleFirstAdminCreate() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzleFirstAdminCreate() pending mocks = %v", gock.Pending())
+	}
+}
+
+func Test_resourceKuzzleFirstAdminRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		wantID  string
+		mock    Mock
+	}{
+		{
+			name:    "Administrator exists",
+			wantErr: false,
+			wantID:  "admin",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/_adminExists",
+				response:   json.RawMessage(`{"result": {"exists": true}}`),
+			},
+		},
+		{
+			name:    "Storage wiped",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/_adminExists",
+				response:   json.RawMessage(`{"result": {"exists": false}}`),
+			},
+		},
+		{
+			name:    "Forbidden",
+			wantErr: true,
+			wantID:  "admin",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 403,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/_adminExists",
+				response:   json.RawMessage(`{"error": {"id": "security.rights.forbidden", "message": "Insufficient permissions to execute server:adminExists"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleFirstAdmin().Schema, map[string]interface{}{})
+			d.SetId("admin")
+
+			diags := resourceKuzzleFirstAdminRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleFirstAdminRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleFirstAdminRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package kuzzle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceKuzzleFixtures() *schema.Resource {
+	return &schema.Resource{
+		Description: "Loads document fixtures into Kuzzle, e.g. to seed a demo environment. They are loaded again when their content changes",
+
+		CreateContext: resourceKuzzleFixturesCreate,
+		ReadContext:   resourceKuzzleFixturesRead,
+		DeleteContext: resourceKuzzleFixturesDelete,
+
+		CustomizeDiff: resourceKuzzleFixturesCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"fixtures": { // Inline fixtures
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{"fixtures", "file"},
+				Description:      "JSON fixtures, e.g. jsonencode({index = {collection = [{create = {_id = \"1\"}}, {field = \"value\"}]}}): the bulk actions and documents of each collection, by index",
+				ValidateDiagFunc: validateJSON(jsonFixtures),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"file": { // Fixtures file path
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Path of a JSON file holding the fixtures, in the same format as the fixtures attribute",
+			},
+			"content_hash": { // Hash of the fixtures, to load them again when they change
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 hash of the loaded fixtures",
+			},
+		},
+	}
+}
+
+func resourceKuzzleFixturesCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	file := d.Get("file").(string)
+	fixtures, err := readFixtures(d.Get("fixtures").(string), file)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle fixtures file %s: %s", file, err)
+	}
+
+	// Inline fixtures are validated at plan time, a file only once it is read
+	var value interface{}
+	if err := json.Unmarshal([]byte(fixtures), &value); err != nil {
+		return diag.Errorf("Error parsing Kuzzle fixtures: %s", err)
+	}
+	if err := jsonFixtures(value); err != nil {
+		return diag.Errorf("Error parsing Kuzzle fixtures: %s", err)
+	}
+
+	if err := config.query(ctx, http.MethodPost, "/admin/_loadFixtures"+config.refreshQuery(""), json.RawMessage(fixtures), nil); err != nil {
+		return diag.Errorf("Error loading Kuzzle fixtures: %s", err)
+	}
+
+	hash := fixturesHash(fixtures)
+	d.SetId(hash[:16])
+	d.Set("content_hash", hash)
+	config.summary.created()
+
+	return nil
+}
+
+// The loaded documents are not read back: the fixtures hold bulk actions, which may have been applied since
+func resourceKuzzleFixturesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+// The loaded documents are left in place, fixtures not telling which documents they created
+func resourceKuzzleFixturesDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+
+	return nil
+}
+
+// resourceKuzzleFixturesCustomizeDiff loads the fixtures again when the content of their file changes.
+// A file missing at plan time is reported when the fixtures are loaded.
+func resourceKuzzleFixturesCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || d.Get("file").(string) == "" {
+		return nil
+	}
+
+	fixtures, err := readFixtures("", d.Get("file").(string))
+	if err != nil {
+		return nil
+	}
+
+	if hash := fixturesHash(fixtures); hash != d.Get("content_hash").(string) {
+		if err := d.SetNew("content_hash", hash); err != nil {
+			return err
+		}
+		return d.ForceNew("content_hash")
+	}
+
+	return nil
+}
+
+// readFixtures returns the inline fixtures, or else the content of the fixtures file
+func readFixtures(fixtures string, file string) (string, error) {
+	if fixtures != "" {
+		return fixtures, nil
+	}
+
+	content, err := ioutil.ReadFile(file)
+
+	return string(content), err
+}
+
+// fixturesHash returns the SHA-256 hash of fixtures, as a hexadecimal string
+func fixturesHash(fixtures string) string {
+	sum := sha256.Sum256([]byte(fixtures))
+
+	return hex.EncodeToString(sum[:])
+}
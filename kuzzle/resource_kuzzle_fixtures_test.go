@@ -0,0 +1,165 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleFixturesCreate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "fixtures.json")
+	if err := ioutil.WriteFile(file, []byte(`{"iot": {"sensors": [{"create": {"_id": "sensor-1"}}, {"name": "lobby"}]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	invalidFile := filepath.Join(t.TempDir(), "invalid.json")
+	if err := ioutil.WriteFile(invalidFile, []byte(`{"iot": [{"create": {"_id": "sensor-1"}}]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		raw      map[string]interface{}
+		wantErr  bool
+		wantHash string
+		mocks    []Mock
+	}{
+		{
+			name:     "Inline fixtures",
+			raw:      map[string]interface{}{"fixtures": `{"iot":{"sensors":[{"create":{"_id":"sensor-1"}},{"name":"lobby"}]}}`},
+			wantErr:  false,
+			wantHash: fixturesHash(`{"iot":{"sensors":[{"create":{"_id":"sensor-1"}},{"name":"lobby"}]}}`),
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/admin/_loadFixtures",
+					response:   json.RawMessage(`{"result": {"acknowledge": true}}`),
+				},
+			},
+		},
+		{
+			name:     "Fixtures file",
+			raw:      map[string]interface{}{"file": file},
+			wantErr:  false,
+			wantHash: fixturesHash(`{"iot": {"sensors": [{"create": {"_id": "sensor-1"}}, {"name": "lobby"}]}}`),
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/admin/_loadFixtures",
+					response:   json.RawMessage(`{"result": {"acknowledge": true}}`),
+				},
+			},
+		},
+		{
+			name:    "Invalid fixtures file",
+			raw:     map[string]interface{}{"file": invalidFile},
+			wantErr: true,
+		},
+		{
+			name:    "Missing fixtures file",
+			raw:     map[string]interface{}{"file": filepath.Join(t.TempDir(), "missing.json")},
+			wantErr: true,
+		},
+		{
+			name:    "Collection not found",
+			raw:     map[string]interface{}{"fixtures": `{"iot":{"sensors":[{"create":{"_id":"sensor-1"}},{"name":"lobby"}]}}`},
+			wantErr: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 404,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/admin/_loadFixtures",
+					response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_collection", "message": "Collection \"sensors\" does not exist."}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleFixtures().Schema, tt.raw)
+			diags := resourceKuzzleFixturesCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512", Refresh: refreshFalse})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleFixturesCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if got := d.Get("content_hash").(string); got != tt.wantHash {
+				t.Errorf("resourceKuzzleFixturesCreate() content_hash = %v, want %v", got, tt.wantHash)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleFixturesCreate() pending mocks = %v", gock.Pending())
+			}
+		})
+	}
+}
+
+// Editing the fixtures file loads it again, the path being unchanged
+func Test_resourceKuzzleFixturesCustomizeDiff(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "fixtures.json")
+	if err := ioutil.WriteFile(file, []byte(`{"iot": {"sensors": [{"create": {}}, {"name": "lobby"}]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := resourceKuzzleFixtures()
+	current := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"file": file})
+	current.SetId("cafe")
+	current.Set("content_hash", fixturesHash(`{"iot": {"sensors": [{"create": {}}, {"name": "lobby"}]}}`))
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"file": file})
+
+	diff, err := r.Diff(context.Background(), current.State(), config, nil)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Errorf("Diff() = %v, want no change for an unchanged file", diff)
+	}
+
+	if err := ioutil.WriteFile(file, []byte(`{"iot": {"sensors": [{"create": {}}, {"name": "garage"}]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	diff, err = r.Diff(context.Background(), current.State(), config, nil)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if diff == nil || !diff.RequiresNew() {
+		t.Errorf("Diff() = %v, want the fixtures to be loaded again", diff)
+	}
+}
+
+func Test_jsonFixtures(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixtures string
+		wantErr  bool
+	}{
+		{name: "Fixtures", fixtures: `{"iot": {"sensors": [{"create": {}}, {"name": "lobby"}]}}`, wantErr: false},
+		{name: "Array", fixtures: `[{"create": {}}]`, wantErr: true},
+		{name: "Collections array", fixtures: `{"iot": [{"create": {}}]}`, wantErr: true},
+		{name: "Documents object", fixtures: `{"iot": {"sensors": {"name": "lobby"}}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.fixtures), &value); err != nil {
+				t.Fatal(err)
+			}
+			if err := jsonFixtures(value); (err != nil) != tt.wantErr {
+				t.Errorf("jsonFixtures() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
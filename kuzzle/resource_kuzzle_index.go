@@ -0,0 +1,159 @@
+package kuzzle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceKuzzleIndex() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages a Kuzzle index",
+
+		CreateContext: resourceKuzzleIndexCreate,
+		ReadContext:   resourceKuzzleIndexRead,
+		UpdateContext: resourceKuzzleIndexUpdate,
+		DeleteContext: resourceKuzzleIndexDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzleIndexImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Index name",
+			},
+			"delete_collections": { // Cascading deletion
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the collections of the index, and their documents, are deleted with it. Destroying an index which still has collections fails otherwise",
+			},
+		},
+	}
+}
+
+func resourceKuzzleIndexCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	index := d.Get("index").(string)
+
+	config := meta.(*Config)
+
+	if err := config.query(ctx, http.MethodPost, "/"+url.PathEscape(index)+"/_create", nil, nil); err != nil {
+		return diag.Errorf("Error creating Kuzzle index %q: %s", index, err)
+	}
+
+	d.SetId(index)
+	config.summary.created()
+
+	return resourceKuzzleIndexRead(ctx, d, meta)
+}
+
+func resourceKuzzleIndexRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	exists, err := indexExists(ctx, config, d.Id())
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle index %q: %s", d.Id(), err)
+	}
+	if !exists {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("index", d.Id())
+
+	return nil
+}
+
+// Only delete_collections can change, it is used on destroy
+func resourceKuzzleIndexUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceKuzzleIndexRead(ctx, d, meta)
+}
+
+// Deleting an index deletes all its collections and documents: unless delete_collections is set,
+// an index which still has collections is left in place
+func resourceKuzzleIndexDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	collections, err := indexCollections(ctx, config, d.Id())
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error listing Kuzzle index %q collections: %s", d.Id(), err)
+	}
+
+	if len(collections) > 0 && !d.Get("delete_collections").(bool) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Kuzzle index %q is not empty", d.Id()),
+			Detail: fmt.Sprintf("The index still has the collections %s. Delete them first, or set delete_collections to delete them, and their documents, with the index.",
+				strings.Join(collections, ", ")),
+		}}
+	}
+
+	// A collection deleted in the meantime does not stop the cascade
+	for _, collection := range collections {
+		err := config.query(ctx, http.MethodDelete, collectionPath(d.Id(), collection), nil, nil)
+		if err != nil && !isNotFound(err) {
+			return diag.Errorf("Error deleting Kuzzle collection %s/%s: %s", d.Id(), collection, err)
+		}
+	}
+
+	err = config.query(ctx, http.MethodDelete, "/"+url.PathEscape(d.Id()), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return diag.Errorf("Error deleting Kuzzle index %q: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// The collections of an imported index are not deleted with it unless delete_collections is set afterwards
+func resourceKuzzleIndexImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("delete_collections", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// indexCollections returns the names of the stored collections of an index, sorted alphabetically, with collection:list
+func indexCollections(ctx context.Context, config *Config, index string) ([]string, error) {
+	var list struct {
+		Collections []struct {
+			Name string `json:"name"`
+		} `json:"collections"`
+	}
+	if err := config.query(ctx, http.MethodGet, "/"+url.PathEscape(index)+"/_list?type=stored", nil, &list); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(list.Collections))
+	for _, collection := range list.Collections {
+		names = append(names, collection.Name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// indexExists tells whether an index exists, with index:exists
+func indexExists(ctx context.Context, config *Config, index string) (bool, error) {
+	var exists bool
+	if err := config.query(ctx, http.MethodGet, "/"+url.PathEscape(index)+"/_exists", nil, &exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,237 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleIndexCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		wantID  string
+		mocks   []Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			wantID:  "nyc-open-data",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/nyc-open-data/_create",
+					response:   json.RawMessage(`{"result": {"acknowledged": true}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/nyc-open-data/_exists",
+					response:   json.RawMessage(`{"result": true}`),
+				},
+			},
+		},
+		{
+			name:    "Already exists",
+			wantErr: true,
+			wantID:  "",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 412,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/nyc-open-data/_create",
+					response:   json.RawMessage(`{"error": {"id": "services.storage.index_already_exists", "message": "Index \"nyc-open-data\" already exists."}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleIndex().Schema, map[string]interface{}{"index": "nyc-open-data"})
+			diags := resourceKuzzleIndexCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleIndexCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleIndexCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleIndexRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		wantID  string
+		mock    Mock
+	}{
+		{
+			name:    "Exists",
+			wantErr: false,
+			wantID:  "nyc-open-data",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/nyc-open-data/_exists",
+				response:   json.RawMessage(`{"result": true}`),
+			},
+		},
+		{
+			name:    "Deleted outside of Terraform",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/nyc-open-data/_exists",
+				response:   json.RawMessage(`{"result": false}`),
+			},
+		},
+		{
+			name:    "Forbidden",
+			wantErr: true,
+			wantID:  "nyc-open-data",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 403,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/nyc-open-data/_exists",
+				response:   json.RawMessage(`{"error": {"message": "Forbidden action [index/exists]"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleIndex().Schema, map[string]interface{}{})
+			d.SetId("nyc-open-data")
+
+			diags := resourceKuzzleIndexRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleIndexRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleIndexRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if !tt.wantErr && tt.wantID != "" && d.Get("index").(string) != tt.wantID {
+				t.Errorf("resourceKuzzleIndexRead() index = %v, want %v", d.Get("index"), tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleIndexDelete(t *testing.T) {
+	list := func(statusCode int, response string) Mock {
+		return Mock{
+			enabled:    true,
+			statusCode: statusCode,
+			method:     "GET",
+			url:        "http://kuzzle:7512",
+			route:      "/nyc-open-data/_list",
+			response:   json.RawMessage(response),
+		}
+	}
+	deletion := func(route string, statusCode int, response string) Mock {
+		return Mock{
+			enabled:    true,
+			statusCode: statusCode,
+			method:     "DELETE",
+			url:        "http://kuzzle:7512",
+			route:      route,
+			response:   json.RawMessage(response),
+		}
+	}
+
+	tests := []struct {
+		name              string
+		deleteCollections bool
+		wantErr           bool
+		mocks             []Mock
+	}{
+		{
+			name:    "Empty index",
+			wantErr: false,
+			mocks: []Mock{
+				list(200, `{"result": {"collections": []}}`),
+				deletion("/nyc-open-data$", 200, `{"result": {"acknowledged": true}}`),
+			},
+		},
+		{
+			name:    "Index with collections",
+			wantErr: true,
+			mocks: []Mock{
+				list(200, `{"result": {"collections": [{"name": "yellow-taxi", "type": "stored"}]}}`),
+			},
+		},
+		{
+			name:              "Collections deleted first",
+			deleteCollections: true,
+			wantErr:           false,
+			mocks: []Mock{
+				list(200, `{"result": {"collections": [{"name": "yellow-taxi", "type": "stored"}, {"name": "green-taxi", "type": "stored"}]}}`),
+				deletion("/nyc-open-data/green-taxi", 200, `{"result": {"acknowledged": true}}`),
+				deletion("/nyc-open-data/yellow-taxi", 404, `{"error": {"id": "services.storage.unknown_collection", "message": "Collection \"yellow-taxi\" does not exist."}}`),
+				deletion("/nyc-open-data$", 200, `{"result": {"acknowledged": true}}`),
+			},
+		},
+		{
+			name:    "Already deleted",
+			wantErr: false,
+			mocks: []Mock{
+				list(404, `{"error": {"id": "services.storage.unknown_index", "message": "Index \"nyc-open-data\" does not exist."}}`),
+			},
+		},
+		{
+			name:    "Forbidden",
+			wantErr: true,
+			mocks: []Mock{
+				list(200, `{"result": {"collections": []}}`),
+				deletion("/nyc-open-data$", 403, `{"error": {"message": "Forbidden action [index/delete]"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleIndex().Schema, map[string]interface{}{
+				"index":              "nyc-open-data",
+				"delete_collections": tt.deleteCollections,
+			})
+			d.SetId("nyc-open-data")
+
+			diags := resourceKuzzleIndexDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleIndexDelete() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if !tt.wantErr && d.Id() != "" {
+				t.Errorf("resourceKuzzleIndexDelete() id = %v, want none", d.Id())
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleIndexDelete() pending mocks = %v", gock.Pending())
+			}
+		})
+	}
+}
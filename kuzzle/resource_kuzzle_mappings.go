@@ -0,0 +1,150 @@
+package kuzzle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// mappingsTree is the mappings of collections, by index then collection, as loaded by admin:loadMappings
+type mappingsTree map[string]map[string]map[string]interface{}
+
+func resourceKuzzleMappings() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages the indexes, collections and mappings of a whole mappings tree, loaded with admin:loadMappings",
+
+		CreateContext: resourceKuzzleMappingsCreate,
+		ReadContext:   resourceKuzzleMappingsRead,
+		UpdateContext: resourceKuzzleMappingsUpdate,
+		DeleteContext: resourceKuzzleMappingsDelete,
+
+		CustomizeDiff: resourceKuzzleMappingsCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"mappings": { // Mappings tree
+				Type:     schema.TypeString,
+				Required: true,
+				Description: "JSON mappings of each collection, by index then collection, e.g. {\"iot\": {\"sensors\": {\"properties\": {...}}}}. " +
+					"Missing indexes and collections are created, and fields can only be added to the existing ones, the removed ones being left in place",
+				ValidateDiagFunc: validateJSON(jsonMappingsTree),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+		},
+	}
+}
+
+func resourceKuzzleMappingsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	raw := d.Get("mappings").(string)
+
+	if err := config.query(ctx, http.MethodPost, "/admin/_loadMappings"+config.refreshQuery(""), json.RawMessage(raw), nil); err != nil {
+		return diag.Errorf("Error loading Kuzzle mappings: %s", err)
+	}
+
+	sum := sha256.Sum256([]byte(raw))
+	d.SetId(hex.EncodeToString(sum[:8]))
+	config.summary.created()
+
+	return resourceKuzzleMappingsRead(ctx, d, meta)
+}
+
+// The collections deleted outside of Terraform are dropped from the state, so that they are loaded again,
+// and the fields of the known mappings are compared to detect drift
+func resourceKuzzleMappingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	var known map[string]map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(d.Get("mappings").(string)), &known); err != nil {
+		return diag.Errorf("Error parsing Kuzzle mappings: %s", err)
+	}
+
+	tree := make(map[string]map[string]json.RawMessage, len(known))
+	for index, collections := range known {
+		for collection, mappings := range collections {
+			var remote map[string]interface{}
+			err := config.query(ctx, http.MethodGet, collectionPath(index, collection)+"/_mapping", nil, &remote)
+			if isNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return diag.Errorf("Error reading Kuzzle collection %s/%s mappings: %s", index, collection, err)
+			}
+
+			// The defaults added by Kuzzle, such as dynamic or _meta, and the fields added by dynamic mappings are not reported as drift
+			current, err := knownMappings(string(mappings), remote)
+			if err != nil {
+				return diag.Errorf("Error reading Kuzzle collection %s/%s mappings: %s", index, collection, err)
+			}
+
+			if tree[index] == nil {
+				tree[index] = make(map[string]json.RawMessage)
+			}
+			tree[index][collection] = json.RawMessage(current)
+		}
+	}
+
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("mappings", string(raw))
+
+	return nil
+}
+
+// Mappings are merged by Kuzzle: the update only adds new indexes, collections and fields,
+// see resourceKuzzleMappingsCustomizeDiff
+func resourceKuzzleMappingsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	if err := config.query(ctx, http.MethodPost, "/admin/_loadMappings"+config.refreshQuery(""), json.RawMessage(d.Get("mappings").(string)), nil); err != nil {
+		return diag.Errorf("Error loading Kuzzle mappings: %s", err)
+	}
+	config.summary.updated()
+
+	return resourceKuzzleMappingsRead(ctx, d, meta)
+}
+
+// Indexes and collections hold documents: they are left in place when the resource is destroyed
+func resourceKuzzleMappingsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+
+	return nil
+}
+
+// resourceKuzzleMappingsCustomizeDiff rejects the mappings that retype fields of a collection at plan time,
+// as loading mappings never recreates collections. Collections removed from the tree are only no longer managed.
+func resourceKuzzleMappingsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("mappings") {
+		return nil
+	}
+
+	old, new := d.GetChange("mappings")
+	var oldTree, newTree mappingsTree
+	if err := json.Unmarshal([]byte(old.(string)), &oldTree); err != nil {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(new.(string)), &newTree); err != nil {
+		return nil
+	}
+
+	for index, collections := range oldTree {
+		for collection, oldMappings := range collections {
+			newMappings, ok := newTree[index][collection]
+			if !ok {
+				continue
+			}
+			if reason := incompatibleProperties(oldMappings["properties"], newMappings["properties"], ""); reason != "" {
+				return fmt.Errorf("Kuzzle collection %s/%s mappings cannot be updated, %s", index, collection, reason)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleMappingsCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		wantErr      bool
+		wantMappings string
+		mocks        []Mock
+	}{
+		{
+			name:         "Success",
+			wantErr:      false,
+			wantMappings: `{"iot":{"sensors":{"properties":{"name":{"type":"keyword"}}}}}`,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/admin/_loadMappings",
+					response:   json.RawMessage(`{"result": {"acknowledge": true}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/iot/sensors/_mapping",
+					response:   json.RawMessage(`{"result": {"dynamic": "true", "_meta": {}, "properties": {"name": {"type": "keyword"}}}}`),
+				},
+			},
+		},
+		{
+			name:    "Invalid mappings",
+			wantErr: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 400,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/admin/_loadMappings",
+					response:   json.RawMessage(`{"error": {"id": "services.storage.invalid_mapping", "message": "No handler for type [keywrd] declared on field [name]"}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleMappings().Schema, map[string]interface{}{
+				"mappings": `{"iot": {"sensors": {"properties": {"name": {"type": "keyword"}}}}}`,
+			})
+			diags := resourceKuzzleMappingsCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleMappingsCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantMappings != "" && d.Get("mappings").(string) != tt.wantMappings {
+				t.Errorf("resourceKuzzleMappingsCreate() mappings = %v, want %v", d.Get("mappings"), tt.wantMappings)
+			}
+		})
+	}
+}
+
+// A collection deleted outside of Terraform is dropped from the state, so that the mappings are loaded again
+func Test_resourceKuzzleMappingsRead(t *testing.T) {
+	defer gock.Off()
+	registerMocks([]Mock{
+		{
+			enabled:    true,
+			statusCode: 200,
+			method:     "GET",
+			url:        "http://kuzzle:7512",
+			route:      "/iot/sensors/_mapping",
+			response:   json.RawMessage(`{"result": {"dynamic": "true", "properties": {"name": {"type": "text"}}}}`),
+		},
+		{
+			enabled:    true,
+			statusCode: 404,
+			method:     "GET",
+			url:        "http://kuzzle:7512",
+			route:      "/iot/gateways/_mapping",
+			response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_collection", "message": "Collection \"gateways\" does not exist."}}`),
+		},
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleMappings().Schema, map[string]interface{}{
+		"mappings": `{"iot": {"sensors": {"properties": {"name": {"type": "keyword"}}}, "gateways": {"properties": {}}}}`,
+	})
+	d.SetId("cafe")
+
+	if diags := resourceKuzzleMappingsRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzleMappingsRead() diags = %v", diags)
+	}
+	want := `{"iot":{"sensors":{"properties":{"name":{"type":"text"}}}}}`
+	if got := d.Get("mappings").(string); got != want {
+		t.Errorf("resourceKuzzleMappingsRead() mappings = %v, want %v", got, want)
+	}
+}
+
+func Test_resourceKuzzleMappingsCustomizeDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings string
+		wantErr  bool
+	}{
+		{name: "Collection added", mappings: `{"iot": {"sensors": {"properties": {"name": {"type": "keyword"}}}, "gateways": {"properties": {}}}}`, wantErr: false},
+		{name: "Collection removed", mappings: `{"iot": {}}`, wantErr: false},
+		{name: "Field retyped", mappings: `{"iot": {"sensors": {"properties": {"name": {"type": "text"}}}}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceKuzzleMappings()
+			current := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"mappings": `{"iot": {"sensors": {"properties": {"name": {"type": "keyword"}}}}}`,
+			})
+			current.SetId("cafe")
+
+			_, err := r.Diff(context.Background(), current.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+				"mappings": tt.mappings,
+			}), nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_jsonMappingsTree(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings string
+		wantErr  bool
+	}{
+		{name: "Tree", mappings: `{"iot": {"sensors": {"properties": {}}}}`, wantErr: false},
+		{name: "Mappings string", mappings: `{"iot": {"sensors": "keyword"}}`, wantErr: true},
+		{name: "Collections array", mappings: `{"iot": ["sensors"]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.mappings), &value); err != nil {
+				t.Fatal(err)
+			}
+			if err := jsonMappingsTree(value); (err != nil) != tt.wantErr {
+				t.Errorf("jsonMappingsTree() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
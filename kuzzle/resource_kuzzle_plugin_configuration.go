@@ -0,0 +1,175 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceKuzzlePluginConfiguration() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages the configuration document of a Kuzzle plugin, in a collection the plugin reads it from. " +
+			"The private storage of plugins is not reachable through the API: the collection must be a regular one",
+
+		CreateContext: resourceKuzzlePluginConfigurationCreate,
+		ReadContext:   resourceKuzzlePluginConfigurationRead,
+		UpdateContext: resourceKuzzlePluginConfigurationUpdate,
+		DeleteContext: resourceKuzzlePluginConfigurationDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzlePluginConfigurationImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"index": { // Index name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Index name",
+			},
+			"collection": { // Collection name
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the collection the plugin reads its configuration from",
+			},
+			"document_id": { // Configuration document identifier
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Identifier of the configuration document",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"configuration": { // Configuration document content
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "JSON configuration of the plugin. Any field added or changed outside of Terraform is reported as drift",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"refresh": { // Refresh mode of the writes
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Refresh mode of the configuration writes: wait_for or false, the provider refresh mode by default",
+				ValidateFunc: validation.StringInSlice([]string{refreshWaitFor, refreshFalse}, false),
+			},
+		},
+	}
+}
+
+func resourceKuzzlePluginConfigurationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	documentID := d.Get("document_id").(string)
+
+	if err := replacePluginConfiguration(ctx, config, d); err != nil {
+		return diag.Errorf("Error creating Kuzzle plugin configuration %s/%s/%s: %s", index, collection, documentID, err)
+	}
+
+	d.SetId(index + "/" + collection + "/" + documentID)
+	config.summary.created()
+
+	return resourceKuzzlePluginConfigurationRead(ctx, d, meta)
+}
+
+func resourceKuzzlePluginConfigurationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index, collection, documentID, err := parsePluginConfigurationID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var document struct {
+		Source map[string]json.RawMessage `json:"_source"`
+	}
+	err = config.query(ctx, http.MethodGet, documentPath(index, collection, documentID), nil, &document)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle plugin configuration %s/%s/%s: %s", index, collection, documentID, err)
+	}
+
+	// The whole document is owned by the resource, only the Kuzzle metadata are left out
+	delete(document.Source, "_kuzzle_info")
+	raw, err := json.Marshal(document.Source)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	configuration, err := normalizeJSON(raw)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle plugin configuration %s/%s/%s: %s", index, collection, documentID, err)
+	}
+
+	d.Set("index", index)
+	d.Set("collection", collection)
+	d.Set("document_id", documentID)
+	d.Set("configuration", configuration)
+
+	return nil
+}
+
+func resourceKuzzlePluginConfigurationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	if d.HasChange("configuration") {
+		if err := replacePluginConfiguration(ctx, config, d); err != nil {
+			return diag.Errorf("Error updating Kuzzle plugin configuration %s: %s", d.Id(), err)
+		}
+		config.summary.updated()
+	}
+
+	return resourceKuzzlePluginConfigurationRead(ctx, d, meta)
+}
+
+func resourceKuzzlePluginConfigurationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	index := d.Get("index").(string)
+	collection := d.Get("collection").(string)
+	documentID := d.Get("document_id").(string)
+
+	path := documentPath(index, collection, documentID) + config.refreshQuery(d.Get("refresh").(string))
+	if err := config.query(ctx, http.MethodDelete, path, nil, nil); err != nil && !isNotFound(err) {
+		return diag.Errorf("Error deleting Kuzzle plugin configuration %s/%s/%s: %s", index, collection, documentID, err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+func resourceKuzzlePluginConfigurationImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, _, err := parsePluginConfigurationID(d.Id()); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// replacePluginConfiguration writes the configuration document with document:createOrReplace
+func replacePluginConfiguration(ctx context.Context, config *Config, d *schema.ResourceData) error {
+	path := documentPath(d.Get("index").(string), d.Get("collection").(string), d.Get("document_id").(string)) +
+		config.refreshQuery(d.Get("refresh").(string))
+
+	return config.query(ctx, http.MethodPut, path, json.RawMessage(d.Get("configuration").(string)), nil)
+}
+
+// parsePluginConfigurationID splits a plugin configuration resource id into the index, collection and document identifier.
+// The document identifier can contain slashes, the index and collection names cannot.
+func parsePluginConfigurationID(id string) (index string, collection string, documentID string, err error) {
+	parts := strings.SplitN(id, "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid Kuzzle plugin configuration id %q, expected <index>/<collection>/<document_id>", id)
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
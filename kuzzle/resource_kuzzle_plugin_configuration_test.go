@@ -0,0 +1,190 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzlePluginConfigurationCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		mocks   []Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/plugins/config/plugin-x",
+					response:   json.RawMessage(`{"result": {"_id": "plugin-x", "created": true}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/plugins/config/plugin-x",
+					response:   json.RawMessage(`{"result": {"_id": "plugin-x", "_source": {"retries": 3, "_kuzzle_info": {"author": "-1"}}}}`),
+				},
+			},
+		},
+		{
+			name:    "Unknown collection",
+			wantErr: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 404,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/plugins/config/plugin-x",
+					response:   json.RawMessage(`{"error": {"id": "services.storage.unknown_collection", "message": "Collection \"config\" does not exist."}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzlePluginConfiguration().Schema, map[string]interface{}{
+				"index":         "plugins",
+				"collection":    "config",
+				"document_id":   "plugin-x",
+				"configuration": `{"retries": 3}`,
+			})
+			diags := resourceKuzzlePluginConfigurationCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzlePluginConfigurationCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if !tt.wantErr && d.Id() != "plugins/config/plugin-x" {
+				t.Errorf("resourceKuzzlePluginConfigurationCreate() id = %q, want %q", d.Id(), "plugins/config/plugin-x")
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzlePluginConfigurationRead(t *testing.T) {
+	tests := []struct {
+		name              string
+		wantID            string
+		wantConfiguration string
+		mocks             []Mock
+	}{
+		{
+			name:              "Field added elsewhere",
+			wantID:            "plugins/config/plugin-x",
+			wantConfiguration: `{"debug":true,"retries":3}`,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/plugins/config/plugin-x",
+					response:   json.RawMessage(`{"result": {"_id": "plugin-x", "_source": {"retries": 3, "debug": true, "_kuzzle_info": {"author": "-1"}}}}`),
+				},
+			},
+		},
+		{
+			name:   "Deleted elsewhere",
+			wantID: "",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 404,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/plugins/config/plugin-x",
+					response:   json.RawMessage(`{"error": {"id": "services.storage.not_found", "message": "Document \"plugin-x\" not found."}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzlePluginConfiguration().Schema, map[string]interface{}{
+				"index":         "plugins",
+				"collection":    "config",
+				"document_id":   "plugin-x",
+				"configuration": `{"retries": 3}`,
+			})
+			d.SetId("plugins/config/plugin-x")
+
+			if diags := resourceKuzzlePluginConfigurationRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+				t.Errorf("resourceKuzzlePluginConfigurationRead() diags = %v", diags)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzlePluginConfigurationRead() id = %q, want %q", d.Id(), tt.wantID)
+			}
+			if tt.wantConfiguration != "" && d.Get("configuration").(string) != tt.wantConfiguration {
+				t.Errorf("resourceKuzzlePluginConfigurationRead() configuration = %v, want %v", d.Get("configuration"), tt.wantConfiguration)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzlePluginConfigurationDelete(t *testing.T) {
+	defer gock.Off()
+	gock.New("http://kuzzle:7512").
+		Delete("/plugins/config/plugin-x").
+		MatchParam("refresh", "wait_for").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"_id": "plugin-x"}}`))
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzlePluginConfiguration().Schema, map[string]interface{}{
+		"index":         "plugins",
+		"collection":    "config",
+		"document_id":   "plugin-x",
+		"configuration": `{"retries": 3}`,
+		"refresh":       refreshWaitFor,
+	})
+	d.SetId("plugins/config/plugin-x")
+
+	if diags := resourceKuzzlePluginConfigurationDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzlePluginConfigurationDelete() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzlePluginConfigurationDelete() pending mocks = %v", gock.Pending())
+	}
+}
+
+func Test_parsePluginConfigurationID(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		wantIndex      string
+		wantCollection string
+		wantDocumentID string
+		wantErr        bool
+	}{
+		{name: "Valid", id: "plugins/config/plugin-x", wantIndex: "plugins", wantCollection: "config", wantDocumentID: "plugin-x"},
+		{name: "Slash in document id", id: "plugins/config/plugin-x/v2", wantIndex: "plugins", wantCollection: "config", wantDocumentID: "plugin-x/v2"},
+		{name: "Missing document id", id: "plugins/config", wantErr: true},
+		{name: "Empty collection", id: "plugins//plugin-x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, collection, documentID, err := parsePluginConfigurationID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePluginConfigurationID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if index != tt.wantIndex || collection != tt.wantCollection || documentID != tt.wantDocumentID {
+				t.Errorf("parsePluginConfigurationID() = %q, %q, %q, want %q, %q, %q", index, collection, documentID, tt.wantIndex, tt.wantCollection, tt.wantDocumentID)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package kuzzle
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// profilePolicy is a Kuzzle security profile policy, as stored by Kuzzle
+type profilePolicy struct {
+	RoleID       string               `json:"roleId"`
+	RestrictedTo []profileRestriction `json:"restrictedTo,omitempty"`
+}
+
+// profileRestriction scopes a profile policy to an index and, optionally, some of its collections
+type profileRestriction struct {
+	Index       string   `json:"index"`
+	Collections []string `json:"collections,omitempty"`
+}
+
+// profileContent is the body of a Kuzzle security profile
+type profileContent struct {
+	Policies  []profilePolicy `json:"policies"`
+	RateLimit int             `json:"rateLimit,omitempty"`
+}
+
+func resourceKuzzleProfile() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages a Kuzzle security profile. It works with every Kuzzle 2 version, the server version is not checked",
+
+		CreateContext: resourceKuzzleProfileCreate,
+		ReadContext:   resourceKuzzleProfileRead,
+		UpdateContext: resourceKuzzleProfileUpdate,
+		DeleteContext: resourceKuzzleProfileDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"profile_id": { // Profile unique identifier
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Profile unique identifier",
+			},
+			"policy": { // Roles granted by the profile
+				Type:        schema.TypeSet,
+				Required:    true,
+				Description: "Role granted by the profile, optionally restricted to some indexes and collections",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Identifier of the granted role",
+						},
+						"restricted_to": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Index and collections the role is restricted to",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"index": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Index name",
+									},
+									"collections": {
+										Type:        schema.TypeList,
+										Optional:    true,
+										Description: "Collection names",
+										Elem:        &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"rate_limit": { // Maximum number of requests per second
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Maximum number of requests per second and per node for users with this profile, only sent to Kuzzle when set",
+			},
+			"refresh": { // Refresh mode of the writes
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Refresh mode of the profile writes: wait_for or false, the provider refresh mode by default",
+				ValidateFunc: validation.StringInSlice([]string{refreshWaitFor, refreshFalse}, false),
+			},
+		},
+	}
+}
+
+func resourceKuzzleProfileCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Get("profile_id").(string)
+
+	config := meta.(*Config)
+
+	if err := createOrReplaceProfile(ctx, config, id, d); err != nil {
+		return diag.Errorf("Error creating Kuzzle profile %q: %s", id, err)
+	}
+
+	d.SetId(id)
+	config.summary.created()
+
+	return resourceKuzzleProfileRead(ctx, d, meta)
+}
+
+func resourceKuzzleProfileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	var profile struct {
+		ID     string         `json:"_id"`
+		Source profileContent `json:"_source"`
+	}
+	err := config.query(ctx, http.MethodGet, "/profiles/"+url.PathEscape(d.Id()), nil, &profile)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle profile %q: %s", d.Id(), err)
+	}
+
+	d.Set("profile_id", d.Id())
+	d.Set("rate_limit", profile.Source.RateLimit)
+	if err := d.Set("policy", flattenProfilePolicies(profile.Source.Policies)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceKuzzleProfileUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	if err := createOrReplaceProfile(ctx, config, d.Id(), d); err != nil {
+		return diag.Errorf("Error updating Kuzzle profile %q: %s", d.Id(), err)
+	}
+	config.summary.updated()
+
+	return resourceKuzzleProfileRead(ctx, d, meta)
+}
+
+func resourceKuzzleProfileDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	path := "/profiles/" + url.PathEscape(d.Id()) + config.refreshQuery(d.Get("refresh").(string))
+	err := config.query(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil && !isNotFound(err) {
+		return diag.Errorf("Error deleting Kuzzle profile %q: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// createOrReplaceProfile writes the profile described by the resource data to Kuzzle
+func createOrReplaceProfile(ctx context.Context, config *Config, id string, d *schema.ResourceData) error {
+	profile := profileContent{
+		Policies:  expandProfilePolicies(d.Get("policy").(*schema.Set).List()),
+		RateLimit: d.Get("rate_limit").(int),
+	}
+
+	path := "/profiles/" + url.PathEscape(id) + "/_createOrReplace" + config.refreshQuery(d.Get("refresh").(string))
+
+	return config.query(ctx, http.MethodPut, path, profile, nil)
+}
+
+// expandProfilePolicies converts the policy blocks of the configuration to Kuzzle policies
+func expandProfilePolicies(raw []interface{}) []profilePolicy {
+	policies := make([]profilePolicy, 0, len(raw))
+	for _, p := range raw {
+		policyMap := p.(map[string]interface{})
+		policy := profilePolicy{RoleID: policyMap["role_id"].(string)}
+
+		for _, r := range policyMap["restricted_to"].([]interface{}) {
+			restrictionMap := r.(map[string]interface{})
+			restriction := profileRestriction{Index: restrictionMap["index"].(string)}
+			for _, collection := range restrictionMap["collections"].([]interface{}) {
+				restriction.Collections = append(restriction.Collections, collection.(string))
+			}
+			policy.RestrictedTo = append(policy.RestrictedTo, restriction)
+		}
+
+		policies = append(policies, policy)
+	}
+
+	sortProfilePolicies(policies)
+
+	return policies
+}
+
+// flattenProfilePolicies converts Kuzzle policies to policy blocks, sorted by role id
+func flattenProfilePolicies(policies []profilePolicy) []interface{} {
+	sortProfilePolicies(policies)
+
+	raw := make([]interface{}, 0, len(policies))
+	for _, policy := range policies {
+		restrictions := make([]interface{}, 0, len(policy.RestrictedTo))
+		for _, restriction := range policy.RestrictedTo {
+			collections := make([]interface{}, 0, len(restriction.Collections))
+			for _, collection := range restriction.Collections {
+				collections = append(collections, collection)
+			}
+			restrictions = append(restrictions, map[string]interface{}{
+				"index":       restriction.Index,
+				"collections": collections,
+			})
+		}
+
+		raw = append(raw, map[string]interface{}{
+			"role_id":       policy.RoleID,
+			"restricted_to": restrictions,
+		})
+	}
+
+	return raw
+}
+
+// sortProfilePolicies sorts policies by role id so that their order never produces a diff
+func sortProfilePolicies(policies []profilePolicy) {
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].RoleID < policies[j].RoleID
+	})
+}
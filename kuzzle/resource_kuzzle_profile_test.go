@@ -0,0 +1,334 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleProfileCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     map[string]interface{}
+		wantErr bool
+		wantID  string
+		mocks   []Mock
+	}{
+		{
+			name: "Success",
+			raw: map[string]interface{}{
+				"profile_id": "editor",
+				"policy": []interface{}{
+					map[string]interface{}{"role_id": "default"},
+				},
+			},
+			wantErr: false,
+			wantID:  "editor",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/profiles/editor/_createOrReplace",
+					response:   json.RawMessage(`{"result": {"_id": "editor"}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/profiles/editor",
+					response:   json.RawMessage(`{"result": {"_id": "editor", "_source": {"policies": [{"roleId": "default"}]}}}`),
+				},
+			},
+		},
+		{
+			name: "Forbidden",
+			raw: map[string]interface{}{
+				"profile_id": "editor",
+				"policy": []interface{}{
+					map[string]interface{}{"role_id": "default"},
+				},
+			},
+			wantErr: true,
+			wantID:  "",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 403,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/profiles/editor/_createOrReplace",
+					response:   json.RawMessage(`{"error": {"message": "Forbidden action"}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleProfile().Schema, tt.raw)
+			diags := resourceKuzzleProfileCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleProfileCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleProfileCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleProfileCreateRefresh(t *testing.T) {
+	tests := []struct {
+		name            string
+		providerRefresh string
+		refresh         string
+		wantRefresh     bool
+	}{
+		{name: "Default", providerRefresh: "", refresh: "", wantRefresh: true},
+		{name: "Provider wait_for", providerRefresh: refreshWaitFor, refresh: "", wantRefresh: true},
+		{name: "Provider false", providerRefresh: refreshFalse, refresh: "", wantRefresh: false},
+		{name: "Resource overrides provider", providerRefresh: refreshFalse, refresh: refreshWaitFor, wantRefresh: true},
+		{name: "Resource false", providerRefresh: refreshWaitFor, refresh: refreshFalse, wantRefresh: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			var refreshed bool
+			gock.
+				New("http://kuzzle:7512").
+				Put("/profiles/editor/_createOrReplace").
+				Filter(func(r *http.Request) bool {
+					refreshed = r.URL.Query().Get("refresh") == refreshWaitFor
+					return true
+				}).
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"_id": "editor"}}`))
+			gock.
+				New("http://kuzzle:7512").
+				Get("/profiles/editor").
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"_id": "editor", "_source": {"policies": [{"roleId": "default"}]}}}`))
+
+			raw := map[string]interface{}{
+				"profile_id": "editor",
+				"policy": []interface{}{
+					map[string]interface{}{"role_id": "default"},
+				},
+			}
+			if tt.refresh != "" {
+				raw["refresh"] = tt.refresh
+			}
+			d := schema.TestResourceDataRaw(t, resourceKuzzleProfile().Schema, raw)
+			config := &Config{Endpoint: "http://kuzzle:7512", Refresh: tt.providerRefresh}
+			if diags := resourceKuzzleProfileCreate(context.Background(), d, config); diags.HasError() {
+				t.Fatalf("resourceKuzzleProfileCreate() diags = %v", diags)
+			}
+			if refreshed != tt.wantRefresh {
+				t.Errorf("resourceKuzzleProfileCreate() refresh=wait_for sent = %v, want %v", refreshed, tt.wantRefresh)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleProfileRead(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantErr       bool
+		wantID        string
+		wantRoleIDs   []string
+		wantRateLimit int
+		mock          Mock
+	}{
+		{
+			name:          "Policies sorted by role id",
+			wantErr:       false,
+			wantID:        "editor",
+			wantRoleIDs:   []string{"admin", "default"},
+			wantRateLimit: 50,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/profiles/editor",
+				response: json.RawMessage(`{"result": {"_id": "editor", "_source": {"rateLimit": 50, "policies": [
+					{"roleId": "default"},
+					{"roleId": "admin", "restrictedTo": [{"index": "nyc-open-data", "collections": ["yellow-taxi"]}]}
+				]}}}`),
+			},
+		},
+		{
+			name:    "Deleted outside of Terraform",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/profiles/editor",
+				response:   json.RawMessage(`{"error": {"message": "Profile not found"}}`),
+			},
+		},
+		{
+			name:    "Connection error",
+			wantErr: true,
+			wantID:  "editor",
+			mock: Mock{
+				enabled: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleProfile().Schema, map[string]interface{}{})
+			d.SetId("editor")
+
+			diags := resourceKuzzleProfileRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleProfileRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleProfileRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if tt.wantRoleIDs == nil {
+				return
+			}
+
+			var gotRoleIDs []string
+			for _, p := range flattenProfilePolicies(expandProfilePolicies(d.Get("policy").(*schema.Set).List())) {
+				gotRoleIDs = append(gotRoleIDs, p.(map[string]interface{})["role_id"].(string))
+			}
+			if !reflect.DeepEqual(gotRoleIDs, tt.wantRoleIDs) {
+				t.Errorf("resourceKuzzleProfileRead() role ids = %v, want %v", gotRoleIDs, tt.wantRoleIDs)
+			}
+			if d.Get("rate_limit").(int) != tt.wantRateLimit {
+				t.Errorf("resourceKuzzleProfileRead() rate_limit = %v, want %v", d.Get("rate_limit"), tt.wantRateLimit)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleProfileDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		mock    Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/profiles/editor",
+				response:   json.RawMessage(`{"result": {"_id": "editor"}}`),
+			},
+		},
+		{
+			name:    "No content",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 204,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/profiles/editor",
+			},
+		},
+		{
+			name:    "Already deleted",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/profiles/editor",
+				response:   json.RawMessage(`{"error": {"message": "Profile not found"}}`),
+			},
+		},
+		{
+			name:    "Profile in use",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 412,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/profiles/editor",
+				response:   json.RawMessage(`{"error": {"message": "The profile is still assigned to users"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleProfile().Schema, map[string]interface{}{})
+			d.SetId("editor")
+
+			diags := resourceKuzzleProfileDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleProfileDelete() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+		})
+	}
+}
+
+// The deletion waits for the profile to be gone from searches, like its creation
+func Test_resourceKuzzleProfileDeleteRefresh(t *testing.T) {
+	defer gock.Off()
+	gock.
+		New("http://kuzzle:7512").
+		Delete("/profiles/editor").
+		MatchParam("refresh", refreshWaitFor).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"_id": "editor"}}`))
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleProfile().Schema, map[string]interface{}{})
+	d.SetId("editor")
+
+	if diags := resourceKuzzleProfileDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzleProfileDelete() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzleProfileDelete() pending mocks = %v", gock.Pending())
+	}
+}
+
+func Test_expandProfilePolicies(t *testing.T) {
+	raw := []interface{}{
+		map[string]interface{}{
+			"role_id": "default",
+			"restricted_to": []interface{}{
+				map[string]interface{}{"index": "nyc-open-data", "collections": []interface{}{"yellow-taxi"}},
+			},
+		},
+		map[string]interface{}{"role_id": "admin", "restricted_to": []interface{}{}},
+	}
+	want := []profilePolicy{
+		{RoleID: "admin"},
+		{RoleID: "default", RestrictedTo: []profileRestriction{{Index: "nyc-open-data", Collections: []string{"yellow-taxi"}}}},
+	}
+
+	if got := expandProfilePolicies(raw); !reflect.DeepEqual(got, want) {
+		t.Errorf("expandProfilePolicies() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceKuzzleRole() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages a Kuzzle security role",
+
+		CreateContext: resourceKuzzleRoleCreate,
+		ReadContext:   resourceKuzzleRoleRead,
+		UpdateContext: resourceKuzzleRoleUpdate,
+		DeleteContext: resourceKuzzleRoleDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"role_id": { // Role unique identifier
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Role unique identifier",
+			},
+			"controllers": { // API actions granted by the role
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "JSON object of the API actions the role grants, by controller, e.g. {\"document\": {\"actions\": {\"get\": true}}}",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"refresh": { // Refresh mode of the writes
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Refresh mode of the role writes: wait_for or false, the provider refresh mode by default",
+				ValidateFunc: validation.StringInSlice([]string{refreshWaitFor, refreshFalse}, false),
+			},
+		},
+	}
+}
+
+func resourceKuzzleRoleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Get("role_id").(string)
+
+	config := meta.(*Config)
+
+	if err := createOrReplaceRole(ctx, config, id, d); err != nil {
+		return diag.Errorf("Error creating Kuzzle role %q: %s", id, err)
+	}
+
+	d.SetId(id)
+	config.summary.created()
+
+	return resourceKuzzleRoleRead(ctx, d, meta)
+}
+
+func resourceKuzzleRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	controllers, err := roleControllers(ctx, config, d.Id())
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle role %q: %s", d.Id(), err)
+	}
+
+	d.Set("role_id", d.Id())
+	d.Set("controllers", controllers)
+
+	return nil
+}
+
+// The role is read before being written, so that it is not written again when Kuzzle already has the desired
+// controllers, e.g. when only refresh changes
+func resourceKuzzleRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	current, err := roleControllers(ctx, config, d.Id())
+	if err != nil && !isNotFound(err) {
+		return diag.Errorf("Error reading Kuzzle role %q: %s", d.Id(), err)
+	}
+	desired, err := normalizeJSON([]byte(d.Get("controllers").(string)))
+	if err != nil {
+		return diag.Errorf("Error updating Kuzzle role %q: %s", d.Id(), err)
+	}
+	if current != "" && current == desired {
+		d.Set("controllers", current)
+		return nil
+	}
+
+	if err := createOrReplaceRole(ctx, config, d.Id(), d); err != nil {
+		return diag.Errorf("Error updating Kuzzle role %q: %s", d.Id(), err)
+	}
+	config.summary.updated()
+
+	return resourceKuzzleRoleRead(ctx, d, meta)
+}
+
+func resourceKuzzleRoleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	path := "/roles/" + url.PathEscape(d.Id()) + config.refreshQuery(d.Get("refresh").(string))
+	err := config.query(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil && !isNotFound(err) {
+		return diag.Errorf("Error deleting Kuzzle role %q: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// roleControllers returns the normalized JSON controllers of a role
+func roleControllers(ctx context.Context, config *Config, id string) (string, error) {
+	var role struct {
+		Source struct {
+			Controllers json.RawMessage `json:"controllers"`
+		} `json:"_source"`
+	}
+	if err := config.query(ctx, http.MethodGet, "/roles/"+url.PathEscape(id), nil, &role); err != nil {
+		return "", err
+	}
+
+	return normalizeJSON(role.Source.Controllers)
+}
+
+// createOrReplaceRole writes the role described by the resource data to Kuzzle
+func createOrReplaceRole(ctx context.Context, config *Config, id string, d *schema.ResourceData) error {
+	role := map[string]interface{}{
+		"controllers": json.RawMessage(d.Get("controllers").(string)),
+	}
+
+	path := "/roles/" + url.PathEscape(id) + "/_createOrReplace" + config.refreshQuery(d.Get("refresh").(string))
+
+	return config.query(ctx, http.MethodPut, path, role, nil)
+}
@@ -0,0 +1,288 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleRoleCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		wantID  string
+		mocks   []Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			wantID:  "reader",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/roles/reader/_createOrReplace",
+					response:   json.RawMessage(`{"result": {"_id": "reader"}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/roles/reader",
+					response:   json.RawMessage(`{"result": {"_id": "reader", "_source": {"controllers": {"document": {"actions": {"get": true}}}}}}`),
+				},
+			},
+		},
+		{
+			name:    "Unknown controller",
+			wantErr: true,
+			wantID:  "",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 400,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/roles/reader/_createOrReplace",
+					response:   json.RawMessage(`{"error": {"id": "security.role.unknown_controller", "message": "Unknown API controller \"documents\"."}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleRole().Schema, map[string]interface{}{
+				"role_id":     "reader",
+				"controllers": `{"document": {"actions": {"get": true}}}`,
+			})
+			diags := resourceKuzzleRoleCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleRoleCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleRoleCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleRoleRead(t *testing.T) {
+	tests := []struct {
+		name            string
+		wantErr         bool
+		wantID          string
+		wantControllers string
+		mock            Mock
+	}{
+		{
+			name:            "Edited in the Admin Console",
+			wantErr:         false,
+			wantID:          "reader",
+			wantControllers: `{"document":{"actions":{"get":true,"search":true}}}`,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/roles/reader",
+				response:   json.RawMessage(`{"result": {"_id": "reader", "_source": {"controllers": {"document": {"actions": {"search": true, "get": true}}}}}}`),
+			},
+		},
+		{
+			name:    "Deleted outside of Terraform",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/roles/reader",
+				response:   json.RawMessage(`{"error": {"id": "security.role.not_found", "message": "Role \"reader\" not found."}}`),
+			},
+		},
+		{
+			name:    "Connection error",
+			wantErr: true,
+			wantID:  "reader",
+			mock: Mock{
+				enabled: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleRole().Schema, map[string]interface{}{})
+			d.SetId("reader")
+
+			diags := resourceKuzzleRoleRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleRoleRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleRoleRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if tt.wantControllers != "" && d.Get("controllers").(string) != tt.wantControllers {
+				t.Errorf("resourceKuzzleRoleRead() controllers = %v, want %v", d.Get("controllers"), tt.wantControllers)
+			}
+		})
+	}
+}
+
+// An unchanged role is not written again, e.g. when only refresh changes
+func Test_resourceKuzzleRoleUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		controllers string
+		wantUpdated int
+		mocks       []Mock
+	}{
+		{
+			name:        "Unchanged role",
+			controllers: `{"document": {"actions": {"get": true}}}`,
+			wantUpdated: 0,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/roles/reader",
+					response:   json.RawMessage(`{"result": {"_id": "reader", "_source": {"controllers": {"document": {"actions": {"get": true}}}}}}`),
+				},
+			},
+		},
+		{
+			name:        "Added controller",
+			controllers: `{"document": {"actions": {"get": true}}, "index": {"actions": {"list": true}}}`,
+			wantUpdated: 1,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/roles/reader",
+					response:   json.RawMessage(`{"result": {"_id": "reader", "_source": {"controllers": {"document": {"actions": {"get": true}}}}}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/roles/reader/_createOrReplace",
+					response:   json.RawMessage(`{"result": {"_id": "reader"}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/roles/reader",
+					response:   json.RawMessage(`{"result": {"_id": "reader", "_source": {"controllers": {"document": {"actions": {"get": true}}, "index": {"actions": {"list": true}}}}}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := updatedResourceData(t, resourceKuzzleRole(), "reader", map[string]interface{}{
+				"role_id":     "reader",
+				"controllers": `{"document": {"actions": {"get": true}}}`,
+				"refresh":     refreshWaitFor,
+			}, map[string]interface{}{
+				"role_id":     "reader",
+				"controllers": tt.controllers,
+				"refresh":     refreshFalse,
+			})
+			config := &Config{Endpoint: "http://kuzzle:7512", summary: &operationSummary{}}
+
+			if diags := resourceKuzzleRoleUpdate(context.Background(), d, config); diags.HasError() {
+				t.Errorf("resourceKuzzleRoleUpdate() diags = %v", diags)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleRoleUpdate() pending mocks = %v", gock.Pending())
+			}
+			if config.summary.Updated != tt.wantUpdated {
+				t.Errorf("resourceKuzzleRoleUpdate() updated = %d, want %d", config.summary.Updated, tt.wantUpdated)
+			}
+			if got := d.Get("controllers").(string); !equivalentJSON([]byte(got), []byte(tt.controllers)) {
+				t.Errorf("resourceKuzzleRoleUpdate() controllers = %v, want %v", got, tt.controllers)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleRoleDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		mock    Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/roles/reader",
+				response:   json.RawMessage(`{"result": {"_id": "reader"}}`),
+			},
+		},
+		{
+			name:    "Already deleted",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/roles/reader",
+				response:   json.RawMessage(`{"error": {"id": "security.role.not_found", "message": "Role \"reader\" not found."}}`),
+			},
+		},
+		{
+			name:    "Role in use",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 412,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/roles/reader",
+				response:   json.RawMessage(`{"error": {"id": "security.role.in_use", "message": "The role \"reader\" is still used by profiles."}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleRole().Schema, map[string]interface{}{})
+			d.SetId("reader")
+
+			diags := resourceKuzzleRoleDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleRoleDelete() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package kuzzle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Ways of handling users that already exist when loading securities
+const (
+	onExistingUsersFail      = "fail"      // Existing users are reported as errors
+	onExistingUsersSkip      = "skip"      // Existing users are left untouched
+	onExistingUsersOverwrite = "overwrite" // Existing users are replaced
+)
+
+// securities is a security bundle, as loaded by admin:loadSecurities
+type securities struct {
+	Roles    map[string]json.RawMessage `json:"roles,omitempty"`
+	Profiles map[string]json.RawMessage `json:"profiles,omitempty"`
+	Users    map[string]json.RawMessage `json:"users,omitempty"`
+}
+
+func resourceKuzzleSecurities() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages a bundle of Kuzzle roles, profiles and users as a whole, loaded with admin:loadSecurities",
+
+		CreateContext: resourceKuzzleSecuritiesCreate,
+		ReadContext:   resourceKuzzleSecuritiesRead,
+		UpdateContext: resourceKuzzleSecuritiesUpdate,
+		DeleteContext: resourceKuzzleSecuritiesDelete,
+
+		Schema: map[string]*schema.Schema{
+			"securities": { // Security bundle
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+				Description: "JSON roles, profiles and users by identifier, e.g. {\"roles\": {\"editor\": {\"controllers\": {...}}}, \"profiles\": {...}, \"users\": {...}}. " +
+					"The roles and profiles removed from the bundle are deleted, as are the removed users listed in created_users",
+				ValidateDiagFunc: validateJSON(jsonSecurities),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"on_existing_users": { // Handling of existing users
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      onExistingUsersFail,
+				Description:  "How the users of the bundle which already exist are handled: fail, skip or overwrite. Skipped and overwritten users are left in place once destroyed, only the ones listed in created_users being deleted",
+				ValidateFunc: validation.StringInSlice([]string{onExistingUsersFail, onExistingUsersSkip, onExistingUsersOverwrite}, false),
+			},
+			"refresh": { // Refresh mode of the writes
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Refresh mode of the security writes: wait_for or false, the provider refresh mode by default",
+				ValidateFunc: validation.StringInSlice([]string{refreshWaitFor, refreshFalse}, false),
+			},
+			"created_users": { // Users created by the resource
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Identifiers of the users of the bundle which did not exist when loaded, the only ones overwritten on updates and deleted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceKuzzleSecuritiesCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	raw := d.Get("securities").(string)
+
+	var bundle securities
+	if err := json.Unmarshal([]byte(raw), &bundle); err != nil {
+		return diag.Errorf("Error parsing Kuzzle securities: %s", err)
+	}
+
+	existing, err := existingSecurities(ctx, config, "users", bundle.Users)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle users: %s", err)
+	}
+	if err := loadSecurities(ctx, config, bundle, d.Get("on_existing_users").(string), d.Get("refresh").(string)); err != nil {
+		return diag.Errorf("Error loading Kuzzle securities: %s", err)
+	}
+
+	sum := sha256.Sum256([]byte(raw))
+	d.SetId(hex.EncodeToString(sum[:8]))
+	if err := d.Set("created_users", createdUsers(bundle.Users, existing)); err != nil {
+		return diag.FromErr(err)
+	}
+	config.summary.created()
+
+	return resourceKuzzleSecuritiesRead(ctx, d, meta)
+}
+
+// The roles, profiles and users deleted outside of Terraform are dropped from the state, so that they are loaded again
+func resourceKuzzleSecuritiesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	var bundle securities
+	if err := json.Unmarshal([]byte(d.Get("securities").(string)), &bundle); err != nil {
+		return diag.Errorf("Error parsing Kuzzle securities: %s", err)
+	}
+
+	missing := false
+	for kind, definitions := range map[string]map[string]json.RawMessage{"roles": bundle.Roles, "profiles": bundle.Profiles, "users": bundle.Users} {
+		existing, err := existingSecurities(ctx, config, kind, definitions)
+		if err != nil {
+			return diag.Errorf("Error reading Kuzzle %s: %s", kind, err)
+		}
+		for id := range definitions {
+			if !existing[id] {
+				delete(definitions, id)
+				missing = true
+			}
+		}
+	}
+
+	if missing {
+		raw, err := json.Marshal(bundle)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("securities", string(raw))
+	}
+
+	return nil
+}
+
+// Roles, profiles and users created by the resource are replaced, the other users being loaded with on_existing_users,
+// then the ones removed from the bundle are deleted, users only when created by the resource
+func resourceKuzzleSecuritiesUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	refresh := d.Get("refresh").(string)
+
+	if !d.HasChange("securities") {
+		return resourceKuzzleSecuritiesRead(ctx, d, meta)
+	}
+
+	old, new := d.GetChange("securities")
+	var oldBundle, newBundle securities
+	if err := json.Unmarshal([]byte(old.(string)), &oldBundle); err != nil {
+		return diag.Errorf("Error parsing Kuzzle securities: %s", err)
+	}
+	if err := json.Unmarshal([]byte(new.(string)), &newBundle); err != nil {
+		return diag.Errorf("Error parsing Kuzzle securities: %s", err)
+	}
+
+	// Users which existed before being loaded, e.g. skipped ones, are not overwritten
+	created := d.Get("created_users").(*schema.Set)
+	managed := securities{Roles: newBundle.Roles, Profiles: newBundle.Profiles, Users: map[string]json.RawMessage{}}
+	added := securities{Users: map[string]json.RawMessage{}}
+	for id, user := range newBundle.Users {
+		if _, ok := oldBundle.Users[id]; ok && created.Contains(id) {
+			managed.Users[id] = user
+		} else {
+			added.Users[id] = user
+		}
+	}
+
+	existing, err := existingSecurities(ctx, config, "users", added.Users)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle users: %s", err)
+	}
+	if err := loadSecurities(ctx, config, managed, onExistingUsersOverwrite, refresh); err != nil {
+		return diag.Errorf("Error loading Kuzzle securities: %s", err)
+	}
+	if len(added.Users) > 0 {
+		if err := loadSecurities(ctx, config, added, d.Get("on_existing_users").(string), refresh); err != nil {
+			return diag.Errorf("Error loading Kuzzle securities: %s", err)
+		}
+	}
+	for id := range managed.Users {
+		existing[id] = false
+	}
+	if err := d.Set("created_users", createdUsers(newBundle.Users, existing)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	removed := securities{
+		Roles:    removedSecurities(oldBundle.Roles, newBundle.Roles),
+		Profiles: removedSecurities(oldBundle.Profiles, newBundle.Profiles),
+		Users:    removedSecurities(oldBundle.Users, newBundle.Users),
+	}
+	if err := deleteSecurities(ctx, config, removed, created, refresh); err != nil {
+		return diag.Errorf("Error deleting Kuzzle securities: %s", err)
+	}
+	config.summary.updated()
+
+	return resourceKuzzleSecuritiesRead(ctx, d, meta)
+}
+
+func resourceKuzzleSecuritiesDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	var bundle securities
+	if err := json.Unmarshal([]byte(d.Get("securities").(string)), &bundle); err != nil {
+		return diag.Errorf("Error parsing Kuzzle securities: %s", err)
+	}
+
+	created := d.Get("created_users").(*schema.Set)
+	if err := deleteSecurities(ctx, config, bundle, created, d.Get("refresh").(string)); err != nil {
+		return diag.Errorf("Error deleting Kuzzle securities: %s", err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// loadSecurities loads a security bundle with admin:loadSecurities
+func loadSecurities(ctx context.Context, config *Config, bundle securities, onExistingUsers string, refresh string) error {
+	params := url.Values{}
+	params.Set("onExistingUsers", onExistingUsers)
+	if config.refreshQuery(refresh) != "" {
+		params.Set("refresh", refreshWaitFor)
+	}
+
+	return config.query(ctx, http.MethodPost, "/admin/_loadSecurities?"+params.Encode(), bundle, nil)
+}
+
+// existingSecurities tells which of the roles, profiles or users exist, with a security:mGet* request
+func existingSecurities(ctx context.Context, config *Config, kind string, definitions map[string]json.RawMessage) (map[string]bool, error) {
+	existing := make(map[string]bool, len(definitions))
+	if len(definitions) == 0 {
+		return existing, nil
+	}
+
+	ids := make([]string, 0, len(definitions))
+	for id := range definitions {
+		ids = append(ids, id)
+	}
+
+	var result struct {
+		Hits []struct {
+			ID string `json:"_id"`
+		} `json:"hits"`
+	}
+	if err := config.query(ctx, http.MethodPost, "/"+kind+"/_mGet", map[string]interface{}{"ids": ids}, &result); err != nil {
+		return nil, err
+	}
+	for _, hit := range result.Hits {
+		existing[hit.ID] = true
+	}
+
+	return existing, nil
+}
+
+// deleteSecurities deletes the users, then the profiles and the roles of a bundle, so that none is still in use.
+// Only the created users are deleted, the ones which existed before being skipped or overwritten are left in place.
+func deleteSecurities(ctx context.Context, config *Config, bundle securities, created *schema.Set, refresh string) error {
+	users := make(map[string]json.RawMessage, len(bundle.Users))
+	for id, user := range bundle.Users {
+		if created.Contains(id) {
+			users[id] = user
+		}
+	}
+
+	for _, group := range []struct {
+		kind        string
+		definitions map[string]json.RawMessage
+	}{
+		{kind: "users", definitions: users},
+		{kind: "profiles", definitions: bundle.Profiles},
+		{kind: "roles", definitions: bundle.Roles},
+	} {
+		for _, id := range sortedRawKeys(group.definitions) {
+			path := "/" + group.kind + "/" + url.PathEscape(id) + config.refreshQuery(refresh)
+			if err := config.query(ctx, http.MethodDelete, path, nil, nil); err != nil && !isNotFound(err) {
+				return fmt.Errorf("%s %q: %s", group.kind, id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// createdUsers returns the identifiers of the users of a bundle which did not exist before it was loaded
+func createdUsers(users map[string]json.RawMessage, existing map[string]bool) []string {
+	created := []string{}
+	for _, id := range sortedRawKeys(users) {
+		if !existing[id] {
+			created = append(created, id)
+		}
+	}
+
+	return created
+}
+
+// removedSecurities returns the definitions of old that are not in new
+func removedSecurities(old map[string]json.RawMessage, new map[string]json.RawMessage) map[string]json.RawMessage {
+	removed := make(map[string]json.RawMessage)
+	for id, definition := range old {
+		if _, ok := new[id]; !ok {
+			removed[id] = definition
+		}
+	}
+
+	return removed
+}
+
+// sortedRawKeys returns the identifiers of security definitions in ascending order
+func sortedRawKeys(definitions map[string]json.RawMessage) []string {
+	keys := make(map[string]interface{}, len(definitions))
+	for id := range definitions {
+		keys[id] = nil
+	}
+
+	return sortedKeys(keys)
+}
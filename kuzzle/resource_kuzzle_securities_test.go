@@ -0,0 +1,410 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const testSecurities = `{
+	"roles": {"editor": {"controllers": {"document": {"actions": {"*": true}}}}},
+	"profiles": {"editor": {"policies": [{"roleId": "editor"}]}},
+	"users": {"ada": {"content": {"profileIds": ["editor"]}}}
+}`
+
+func Test_resourceKuzzleSecuritiesCreate(t *testing.T) {
+	tests := []struct {
+		name             string
+		wantErr          bool
+		wantCreatedUsers []string
+		mocks            []Mock
+	}{
+		{
+			name:             "Success",
+			wantErr:          false,
+			wantCreatedUsers: []string{"ada"},
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/users/_mGet",
+					response:   json.RawMessage(`{"result": {"hits": []}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/admin/_loadSecurities",
+					response:   json.RawMessage(`{"result": {"acknowledge": true}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/roles/_mGet",
+					response:   json.RawMessage(`{"result": {"hits": [{"_id": "editor"}]}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/profiles/_mGet",
+					response:   json.RawMessage(`{"result": {"hits": [{"_id": "editor"}]}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/users/_mGet",
+					response:   json.RawMessage(`{"result": {"hits": [{"_id": "ada"}]}}`),
+				},
+			},
+		},
+		{
+			name:    "Existing user",
+			wantErr: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/users/_mGet",
+					response:   json.RawMessage(`{"result": {"hits": [{"_id": "ada"}]}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 400,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/admin/_loadSecurities",
+					response:   json.RawMessage(`{"error": {"id": "security.user.prevent_overwrite", "message": "Cannot overwrite existing users: ada"}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleSecurities().Schema, map[string]interface{}{
+				"securities": testSecurities,
+			})
+			diags := resourceKuzzleSecuritiesCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleSecuritiesCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if (d.Id() != "") == tt.wantErr {
+				t.Errorf("resourceKuzzleSecuritiesCreate() id = %q, wantErr %v", d.Id(), tt.wantErr)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleSecuritiesCreate() pending mocks = %v", gock.Pending())
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := expandStringSet(d.Get("created_users").(*schema.Set)); !reflect.DeepEqual(got, tt.wantCreatedUsers) {
+				t.Errorf("resourceKuzzleSecuritiesCreate() created_users = %v, want %v", got, tt.wantCreatedUsers)
+			}
+		})
+	}
+}
+
+// A user deleted outside of Terraform is dropped from the state, so that the bundle is loaded again
+func Test_resourceKuzzleSecuritiesRead(t *testing.T) {
+	defer gock.Off()
+	registerMocks([]Mock{
+		{
+			enabled:    true,
+			statusCode: 200,
+			method:     "POST",
+			url:        "http://kuzzle:7512",
+			route:      "/roles/_mGet",
+			response:   json.RawMessage(`{"result": {"hits": [{"_id": "editor"}]}}`),
+		},
+		{
+			enabled:    true,
+			statusCode: 200,
+			method:     "POST",
+			url:        "http://kuzzle:7512",
+			route:      "/profiles/_mGet",
+			response:   json.RawMessage(`{"result": {"hits": [{"_id": "editor"}]}}`),
+		},
+		{
+			enabled:    true,
+			statusCode: 200,
+			method:     "POST",
+			url:        "http://kuzzle:7512",
+			route:      "/users/_mGet",
+			response:   json.RawMessage(`{"result": {"hits": []}}`),
+		},
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleSecurities().Schema, map[string]interface{}{
+		"securities": testSecurities,
+	})
+	d.SetId("cafe")
+
+	if diags := resourceKuzzleSecuritiesRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzleSecuritiesRead() diags = %v", diags)
+	}
+	want := `{"profiles":{"editor":{"policies":[{"roleId":"editor"}]}},"roles":{"editor":{"controllers":{"document":{"actions":{"*":true}}}}}}`
+	if got := d.Get("securities").(string); !equivalentJSON([]byte(got), []byte(want)) {
+		t.Errorf("resourceKuzzleSecuritiesRead() securities = %v, want %v", got, want)
+	}
+}
+
+// Users created by the resource are overwritten, the other ones follow on_existing_users, and removed definitions are deleted
+func Test_resourceKuzzleSecuritiesUpdate(t *testing.T) {
+	tests := []struct {
+		name             string
+		onExistingUsers  string
+		createdUsers     []interface{}
+		wantOverwritten  string
+		wantLoaded       string
+		existingUsers    string
+		wantCreatedUsers []string
+	}{
+		{
+			name:             "Created user overwritten",
+			onExistingUsers:  onExistingUsersFail,
+			createdUsers:     []interface{}{"ada"},
+			wantOverwritten:  `{"profiles":{"editor":{"policies":[{"roleId":"editor"}]}},"users":{"ada":{"content":{"profileIds":["editor"]}}}}`,
+			wantLoaded:       `{"users":{"grace":{"content":{"profileIds":["editor"]}}}}`,
+			existingUsers:    `[]`,
+			wantCreatedUsers: []string{"ada", "grace"},
+		},
+		{
+			name:             "Skipped user left untouched",
+			onExistingUsers:  onExistingUsersSkip,
+			createdUsers:     []interface{}{},
+			wantOverwritten:  `{"profiles":{"editor":{"policies":[{"roleId":"editor"}]}}}`,
+			wantLoaded:       `{"users":{"ada":{"content":{"profileIds":["editor"]}},"grace":{"content":{"profileIds":["editor"]}}}}`,
+			existingUsers:    `[{"_id": "ada"}]`,
+			wantCreatedUsers: []string{"grace"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("http://kuzzle:7512").
+				Post("/users/_mGet").
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"hits": ` + tt.existingUsers + `}}`))
+			gock.New("http://kuzzle:7512").
+				Post("/admin/_loadSecurities").
+				MatchParam("onExistingUsers", "overwrite").
+				BodyString(tt.wantOverwritten).
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"acknowledge": true}}`))
+			gock.New("http://kuzzle:7512").
+				Post("/admin/_loadSecurities").
+				MatchParam("onExistingUsers", tt.onExistingUsers).
+				BodyString(tt.wantLoaded).
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"acknowledge": true}}`))
+			gock.New("http://kuzzle:7512").
+				Delete("/roles/editor").
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"_id": "editor"}}`))
+			registerMocks([]Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/profiles/_mGet",
+					response:   json.RawMessage(`{"result": {"hits": [{"_id": "editor"}]}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "POST",
+					url:        "http://kuzzle:7512",
+					route:      "/users/_mGet",
+					response:   json.RawMessage(`{"result": {"hits": [{"_id": "ada"}, {"_id": "grace"}]}}`),
+				},
+			})
+
+			// The created users are computed, so they are set in the state rather than in the configuration
+			r := resourceKuzzleSecurities()
+			current := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"securities":        testSecurities,
+				"on_existing_users": tt.onExistingUsers,
+			})
+			current.SetId("cafe")
+			current.Set("created_users", tt.createdUsers)
+			state := current.State()
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+				"securities": `{
+					"profiles": {"editor": {"policies": [{"roleId": "editor"}]}},
+					"users": {"ada": {"content": {"profileIds": ["editor"]}}, "grace": {"content": {"profileIds": ["editor"]}}}
+				}`,
+				"on_existing_users": tt.onExistingUsers,
+			}), nil)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			d, err := schema.InternalMap(r.Schema).Data(state, diff)
+			if err != nil {
+				t.Fatalf("Data() error = %v", err)
+			}
+
+			if diags := resourceKuzzleSecuritiesUpdate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512", Refresh: refreshFalse}); diags.HasError() {
+				t.Errorf("resourceKuzzleSecuritiesUpdate() diags = %v", diags)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleSecuritiesUpdate() pending mocks = %v", gock.Pending())
+			}
+			if got := expandStringSet(d.Get("created_users").(*schema.Set)); !reflect.DeepEqual(got, tt.wantCreatedUsers) {
+				t.Errorf("resourceKuzzleSecuritiesUpdate() created_users = %v, want %v", got, tt.wantCreatedUsers)
+			}
+		})
+	}
+}
+
+// A user which existed before being overwritten is left in place once removed from the bundle
+func Test_resourceKuzzleSecuritiesUpdateRemovedUser(t *testing.T) {
+	defer gock.Off()
+	registerMocks([]Mock{
+		{
+			enabled:    true,
+			statusCode: 200,
+			method:     "POST",
+			url:        "http://kuzzle:7512",
+			route:      "/admin/_loadSecurities",
+			response:   json.RawMessage(`{"result": {"acknowledge": true}}`),
+		},
+		{
+			enabled:    true,
+			statusCode: 200,
+			method:     "POST",
+			url:        "http://kuzzle:7512",
+			route:      "/roles/_mGet",
+			response:   json.RawMessage(`{"result": {"hits": [{"_id": "editor"}]}}`),
+		},
+		{
+			enabled:    true,
+			statusCode: 200,
+			method:     "POST",
+			url:        "http://kuzzle:7512",
+			route:      "/profiles/_mGet",
+			response:   json.RawMessage(`{"result": {"hits": [{"_id": "editor"}]}}`),
+		},
+	})
+
+	r := resourceKuzzleSecurities()
+	current := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"securities":        testSecurities,
+		"on_existing_users": onExistingUsersOverwrite,
+	})
+	current.SetId("cafe")
+	current.Set("created_users", []interface{}{})
+	state := current.State()
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"securities": `{
+			"roles": {"editor": {"controllers": {"document": {"actions": {"*": true}}}}},
+			"profiles": {"editor": {"policies": [{"roleId": "editor"}]}}
+		}`,
+		"on_existing_users": onExistingUsersOverwrite,
+	}), nil)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("Data() error = %v", err)
+	}
+
+	if diags := resourceKuzzleSecuritiesUpdate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512", Refresh: refreshFalse}); diags.HasError() {
+		t.Errorf("resourceKuzzleSecuritiesUpdate() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzleSecuritiesUpdate() pending mocks = %v", gock.Pending())
+	}
+}
+
+// Only the users created by the resource are deleted, whatever on_existing_users is
+func Test_resourceKuzzleSecuritiesDelete(t *testing.T) {
+	tests := []struct {
+		name            string
+		onExistingUsers string
+		createdUsers    []interface{}
+		routes          []string
+	}{
+		{name: "Users deleted first", onExistingUsers: onExistingUsersFail, createdUsers: []interface{}{"ada"}, routes: []string{"/users/ada", "/profiles/editor", "/roles/editor"}},
+		{name: "Created users deleted when skipping", onExistingUsers: onExistingUsersSkip, createdUsers: []interface{}{"ada"}, routes: []string{"/users/ada", "/profiles/editor", "/roles/editor"}},
+		{name: "Skipped users left in place", onExistingUsers: onExistingUsersSkip, createdUsers: []interface{}{}, routes: []string{"/profiles/editor", "/roles/editor"}},
+		{name: "Overwritten users left in place", onExistingUsers: onExistingUsersOverwrite, createdUsers: []interface{}{}, routes: []string{"/profiles/editor", "/roles/editor"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+
+			var mocks []Mock
+			for _, route := range tt.routes {
+				mocks = append(mocks, Mock{
+					enabled:    true,
+					statusCode: 200,
+					method:     "DELETE",
+					url:        "http://kuzzle:7512",
+					route:      route,
+					response:   json.RawMessage(`{"result": {"acknowledged": true}}`),
+				})
+			}
+			registerMocks(mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleSecurities().Schema, map[string]interface{}{
+				"securities":        testSecurities,
+				"on_existing_users": tt.onExistingUsers,
+			})
+			d.SetId("cafe")
+			d.Set("created_users", tt.createdUsers)
+
+			if diags := resourceKuzzleSecuritiesDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+				t.Errorf("resourceKuzzleSecuritiesDelete() diags = %v", diags)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleSecuritiesDelete() pending mocks = %v", gock.Pending())
+			}
+		})
+	}
+}
+
+func Test_jsonSecurities(t *testing.T) {
+	tests := []struct {
+		name       string
+		securities string
+		wantErr    bool
+	}{
+		{name: "Bundle", securities: testSecurities, wantErr: false},
+		{name: "Roles only", securities: `{"roles": {"editor": {"controllers": {}}}}`, wantErr: false},
+		{name: "Unknown kind", securities: `{"groups": {"editor": {}}}`, wantErr: true},
+		{name: "Roles array", securities: `{"roles": [{"controllers": {}}]}`, wantErr: true},
+		{name: "User string", securities: `{"users": {"ada": "editor"}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.securities), &value); err != nil {
+				t.Fatal(err)
+			}
+			if err := jsonSecurities(value); (err != nil) != tt.wantErr {
+				t.Errorf("jsonSecurities() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// securityMappingTargets are the security collections whose mappings can be extended
+var securityMappingTargets = []string{"users", "profiles", "roles"}
+
+func resourceKuzzleSecurityMapping() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages the mappings of a Kuzzle security collection, e.g. to declare the fields of the users content",
+
+		CreateContext: resourceKuzzleSecurityMappingCreate,
+		ReadContext:   resourceKuzzleSecurityMappingRead,
+		UpdateContext: resourceKuzzleSecurityMappingUpdate,
+		DeleteContext: resourceKuzzleSecurityMappingDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzleSecurityMappingImport,
+		},
+
+		CustomizeDiff: resourceKuzzleSecurityMappingCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"target": { // Security collection
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Security collection whose mappings are managed: users, profiles or roles",
+				ValidateFunc: validation.StringInSlice(securityMappingTargets, false),
+			},
+			"mappings": { // Security collection mappings
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "JSON mappings of the security collection, e.g. {\"properties\": {\"content\": {\"properties\": {...}}}}. Fields can only be added, the ones defined by Kuzzle are not reported as drift",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+		},
+	}
+}
+
+func resourceKuzzleSecurityMappingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	target := d.Get("target").(string)
+
+	if err := config.query(ctx, http.MethodPut, "/"+target+"/_mapping", json.RawMessage(d.Get("mappings").(string)), nil); err != nil {
+		return diag.Errorf("Error updating Kuzzle %s mappings: %s", target, err)
+	}
+
+	d.SetId(target)
+	config.summary.created()
+
+	return resourceKuzzleSecurityMappingRead(ctx, d, meta)
+}
+
+func resourceKuzzleSecurityMappingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	target := d.Id()
+
+	var remote struct {
+		Mapping map[string]interface{} `json:"mapping"`
+	}
+	if err := config.query(ctx, http.MethodGet, "/"+target+"/_mapping", nil, &remote); err != nil {
+		return diag.Errorf("Error reading Kuzzle %s mappings: %s", target, err)
+	}
+
+	mappings, err := knownProperties(d.Get("mappings").(string), remote.Mapping)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle %s mappings: %s", target, err)
+	}
+
+	d.Set("target", target)
+	d.Set("mappings", mappings)
+
+	return nil
+}
+
+// Mappings are merged by Kuzzle: the update only adds new fields, see resourceKuzzleSecurityMappingCustomizeDiff
+func resourceKuzzleSecurityMappingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	target := d.Get("target").(string)
+
+	if err := config.query(ctx, http.MethodPut, "/"+target+"/_mapping", json.RawMessage(d.Get("mappings").(string)), nil); err != nil {
+		return diag.Errorf("Error updating Kuzzle %s mappings: %s", target, err)
+	}
+	config.summary.updated()
+
+	return resourceKuzzleSecurityMappingRead(ctx, d, meta)
+}
+
+// Mappings cannot be removed from a security collection: they are left as they are when the resource is destroyed
+func resourceKuzzleSecurityMappingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+
+	return nil
+}
+
+// The whole mappings returned by Kuzzle are imported, they show up as a change until they are set in the configuration
+func resourceKuzzleSecurityMappingImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	for _, target := range securityMappingTargets {
+		if d.Id() == target {
+			d.Set("target", target)
+			d.Set("mappings", "")
+			return []*schema.ResourceData{d}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid Kuzzle security mapping id %q, expected one of users, profiles or roles", d.Id())
+}
+
+// resourceKuzzleSecurityMappingCustomizeDiff rejects the mappings that retype fields at plan time,
+// as security collections cannot be recreated
+func resourceKuzzleSecurityMappingCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("mappings") {
+		return nil
+	}
+
+	if reason := incompatibleMappingsChange(d); reason != "" {
+		return fmt.Errorf("Kuzzle %s mappings cannot be updated, %s", d.Id(), reason)
+	}
+
+	return nil
+}
+
+// knownProperties returns the remote mapping properties restricted to the properties of the known mappings at every nesting level,
+// as {"properties": {...}}, so that the fields defined by Kuzzle or added by dynamic mappings are not reported as drift.
+// Every property is kept when nothing is known, e.g. on import.
+func knownProperties(known string, remote map[string]interface{}) (string, error) {
+	var knownMappings struct {
+		Properties map[string]interface{} `json:"properties"`
+	}
+	if known != "" {
+		if err := json.Unmarshal([]byte(known), &knownMappings); err != nil {
+			return "", err
+		}
+	}
+
+	properties := remote
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	if known != "" {
+		properties = knownMappingProperties(knownMappings.Properties, remote)
+	}
+
+	raw, err := json.Marshal(map[string]interface{}{"properties": properties})
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeJSON(raw)
+}
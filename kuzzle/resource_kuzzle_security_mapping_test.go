@@ -0,0 +1,141 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleSecurityMappingCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		wantErr      bool
+		wantID       string
+		wantMappings string
+		mocks        []Mock
+	}{
+		{
+			name:         "Success",
+			wantErr:      false,
+			wantID:       "users",
+			wantMappings: `{"properties":{"content":{"properties":{"email":{"type":"keyword"}}}}}`,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/users/_mapping",
+					response:   json.RawMessage(`{"result": {"properties": {"content": {"properties": {"email": {"type": "keyword"}}}}}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/users/_mapping",
+					response: json.RawMessage(`{"result": {"mapping": {
+						"profileIds": {"type": "keyword"},
+						"content": {"properties": {"email": {"type": "keyword"}}}
+					}}}`),
+				},
+			},
+		},
+		{
+			name:    "Forbidden",
+			wantErr: true,
+			wantID:  "",
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 403,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/users/_mapping",
+					response:   json.RawMessage(`{"error": {"id": "security.rights.forbidden", "message": "Insufficient permissions to execute security:updateUserMapping"}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleSecurityMapping().Schema, map[string]interface{}{
+				"target":   "users",
+				"mappings": `{"properties": {"content": {"properties": {"email": {"type": "keyword"}}}}}`,
+			})
+			diags := resourceKuzzleSecurityMappingCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleSecurityMappingCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleSecurityMappingCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if tt.wantMappings != "" && d.Get("mappings").(string) != tt.wantMappings {
+				t.Errorf("resourceKuzzleSecurityMappingCreate() mappings = %v, want %v", d.Get("mappings"), tt.wantMappings)
+			}
+		})
+	}
+}
+
+func Test_knownProperties(t *testing.T) {
+	remote := map[string]interface{}{
+		"profileIds": map[string]interface{}{"type": "keyword"},
+		"content":    map[string]interface{}{"properties": map[string]interface{}{"email": map[string]interface{}{"type": "keyword"}}},
+	}
+	tests := []struct {
+		name  string
+		known string
+		want  string
+	}{
+		{name: "Known properties", known: `{"properties": {"content": {}}}`, want: `{"properties":{"content":{"properties":{"email":{"type":"keyword"}}}}}`},
+		{name: "Removed property", known: `{"properties": {"content": {}, "nickname": {"type": "text"}}}`, want: `{"properties":{"content":{"properties":{"email":{"type":"keyword"}}}}}`},
+		{name: "Import", known: "", want: `{"properties":{"content":{"properties":{"email":{"type":"keyword"}}},"profileIds":{"type":"keyword"}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := knownProperties(tt.known, remote)
+			if err != nil {
+				t.Fatalf("knownProperties() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("knownProperties() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleSecurityMappingCustomizeDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings string
+		wantErr  bool
+	}{
+		{name: "Field added", mappings: `{"properties": {"content": {"properties": {"email": {"type": "keyword"}, "age": {"type": "integer"}}}}}`, wantErr: false},
+		{name: "Field retyped", mappings: `{"properties": {"content": {"properties": {"email": {"type": "text"}}}}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceKuzzleSecurityMapping()
+			current := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"target":   "users",
+				"mappings": `{"properties": {"content": {"properties": {"email": {"type": "keyword"}}}}}`,
+			})
+			current.SetId("users")
+
+			_, err := r.Diff(context.Background(), current.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+				"target":   "users",
+				"mappings": tt.mappings,
+			}), nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// localStrategy is the username/password authentication strategy
+const localStrategy = "local"
+
+func resourceKuzzleUser() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages a Kuzzle user",
+
+		CreateContext: resourceKuzzleUserCreate,
+		ReadContext:   resourceKuzzleUserRead,
+		UpdateContext: resourceKuzzleUserUpdate,
+		DeleteContext: resourceKuzzleUserDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzleUserImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"kuid": { // User unique identifier
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Kuzzle user identifier",
+			},
+			"profile_ids": { // Profiles of the user
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "Identifiers of the profiles assigned to the user",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"content": { // Custom user content
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "{}",
+				Description:      "JSON content of the user, besides its profiles, e.g. {\"name\": ...}. Only the configured fields are checked for drift",
+				ValidateDiagFunc: validateJSON(jsonObject),
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"local_credentials": { // Credentials of the local strategy
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Username and password of the local strategy. They are never read back, use kuzzle_credentials to check them for drift",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"username": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Username",
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "Password",
+						},
+					},
+				},
+			},
+			"refresh": { // Refresh mode of the writes
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Refresh mode of the user writes: wait_for or false, the provider refresh mode by default",
+				ValidateFunc: validation.StringInSlice([]string{refreshWaitFor, refreshFalse}, false),
+			},
+		},
+	}
+}
+
+func resourceKuzzleUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	kuid := d.Get("kuid").(string)
+
+	content, err := userContent(d.Get("content").(string), d.Get("profile_ids").(*schema.Set))
+	if err != nil {
+		return diag.Errorf("Error creating Kuzzle user %q: %s", kuid, err)
+	}
+
+	body := map[string]interface{}{"content": content}
+	if credentials := localCredentials(d.Get("local_credentials").([]interface{})); credentials != nil {
+		body["credentials"] = map[string]interface{}{localStrategy: credentials}
+	}
+
+	path := userPath(kuid) + "/_create" + config.refreshQuery(d.Get("refresh").(string))
+	if err := config.query(ctx, http.MethodPost, path, body, nil); err != nil {
+		return diag.Errorf("Error creating Kuzzle user %q: %s", kuid, err)
+	}
+
+	d.SetId(kuid)
+	config.summary.created()
+
+	return resourceKuzzleUserRead(ctx, d, meta)
+}
+
+func resourceKuzzleUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	var user struct {
+		ID     string                 `json:"_id"`
+		Source map[string]interface{} `json:"_source"`
+	}
+	err := config.query(ctx, http.MethodGet, userPath(d.Id()), nil, &user)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle user %q: %s", d.Id(), err)
+	}
+
+	var profileIDs []interface{}
+	if ids, ok := user.Source["profileIds"].([]interface{}); ok {
+		profileIDs = ids
+	}
+
+	// The profiles have their own attribute, and the metadata added by Kuzzle is not part of the content
+	delete(user.Source, "profileIds")
+	delete(user.Source, "_kuzzle_info")
+	content, err := knownFields(d.Get("content").(string), user.Source)
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle user %q: %s", d.Id(), err)
+	}
+
+	d.Set("kuid", d.Id())
+	d.Set("content", content)
+	if err := d.Set("profile_ids", profileIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceKuzzleUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	refresh := config.refreshQuery(d.Get("refresh").(string))
+
+	if d.HasChanges("content", "profile_ids") {
+		content, err := userContent(d.Get("content").(string), d.Get("profile_ids").(*schema.Set))
+		if err != nil {
+			return diag.Errorf("Error updating Kuzzle user %q: %s", d.Id(), err)
+		}
+
+		// Updates are merged into the user: the fields removed from the content are cleared
+		old, _ := d.GetChange("content")
+		var oldContent map[string]interface{}
+		if err := json.Unmarshal([]byte(old.(string)), &oldContent); err == nil {
+			for field := range oldContent {
+				if _, ok := content[field]; !ok {
+					content[field] = nil
+				}
+			}
+		}
+
+		if err := config.query(ctx, http.MethodPut, userPath(d.Id())+"/_update"+refresh, content, nil); err != nil {
+			return diag.Errorf("Error updating Kuzzle user %q: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("local_credentials") {
+		if err := updateLocalCredentials(ctx, config, d); err != nil {
+			return diag.Errorf("Error updating Kuzzle user %q local credentials: %s", d.Id(), err)
+		}
+	}
+	config.summary.updated()
+
+	return resourceKuzzleUserRead(ctx, d, meta)
+}
+
+func resourceKuzzleUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	path := userPath(d.Id()) + config.refreshQuery(d.Get("refresh").(string))
+	err := config.query(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil && !isNotFound(err) {
+		return diag.Errorf("Error deleting Kuzzle user %q: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// The whole content is imported, and the local credentials cannot be read back: they show up as changes
+// until they are set in the configuration
+func resourceKuzzleUserImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("kuid", d.Id())
+	d.Set("content", "")
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// updateLocalCredentials creates, updates or deletes the local credentials of a user,
+// depending on whether they are added, changed or removed from the configuration
+func updateLocalCredentials(ctx context.Context, config *Config, d *schema.ResourceData) error {
+	old, new := d.GetChange("local_credentials")
+	oldCredentials := localCredentials(old.([]interface{}))
+	newCredentials := localCredentials(new.([]interface{}))
+	path := credentialsPath(localStrategy, d.Id())
+
+	switch {
+	case oldCredentials == nil:
+		return config.query(ctx, http.MethodPost, path+"/_create", newCredentials, nil)
+	case newCredentials == nil:
+		err := config.query(ctx, http.MethodDelete, path, nil, nil)
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	default:
+		return config.query(ctx, http.MethodPut, path+"/_update", newCredentials, nil)
+	}
+}
+
+// userContent returns the content sent to Kuzzle for a user: its JSON content along with its profiles
+func userContent(raw string, profileIDs *schema.Set) (map[string]interface{}, error) {
+	content := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(raw), &content); err != nil {
+		return nil, err
+	}
+
+	content["profileIds"] = expandStringSet(profileIDs)
+
+	return content, nil
+}
+
+// localCredentials converts the local_credentials block to the credentials of the local strategy, nil if it is not set
+func localCredentials(raw []interface{}) map[string]interface{} {
+	if len(raw) == 0 || raw[0] == nil {
+		return nil
+	}
+
+	block := raw[0].(map[string]interface{})
+
+	return map[string]interface{}{
+		"username": block["username"].(string),
+		"password": block["password"].(string),
+	}
+}
+
+// expandStringSet converts a set of strings to a sorted slice
+func expandStringSet(set *schema.Set) []string {
+	values := make([]string, 0, set.Len())
+	for _, v := range set.List() {
+		values = append(values, v.(string))
+	}
+	sort.Strings(values)
+
+	return values
+}
+
+// userPath returns the route of a user
+func userPath(kuid string) string {
+	return "/users/" + url.PathEscape(kuid)
+}
@@ -0,0 +1,158 @@
+package kuzzle
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceKuzzleUserProfileAttachment() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages the profiles of an existing Kuzzle user, the rest of the user being left to whoever owns it, " +
+			"e.g. another team or an identity sync job",
+
+		CreateContext: resourceKuzzleUserProfileAttachmentCreate,
+		ReadContext:   resourceKuzzleUserProfileAttachmentRead,
+		UpdateContext: resourceKuzzleUserProfileAttachmentUpdate,
+		DeleteContext: resourceKuzzleUserProfileAttachmentDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKuzzleUserProfileAttachmentImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"kuid": { // User unique identifier
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Identifier of the existing Kuzzle user",
+			},
+			"profile_ids": { // Profiles of the user
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "Identifiers of the profiles assigned to the user",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"previous_profile_ids": { // Profiles of the user before the attachment
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Identifiers of the profiles the user had before the attachment, assigned again when it is destroyed",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"refresh": { // Refresh mode of the writes
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Refresh mode of the user writes: wait_for or false, the provider refresh mode by default",
+				ValidateFunc: validation.StringInSlice([]string{refreshWaitFor, refreshFalse}, false),
+			},
+		},
+	}
+}
+
+func resourceKuzzleUserProfileAttachmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	kuid := d.Get("kuid").(string)
+
+	// The user is owned elsewhere: it must exist, and its current profiles are kept to be assigned back on destroy
+	previous, err := userProfileIDs(ctx, config, kuid)
+	if isNotFound(err) {
+		return diag.Errorf("Error attaching profiles to Kuzzle user %q: the user does not exist", kuid)
+	}
+	if err != nil {
+		return diag.Errorf("Error attaching profiles to Kuzzle user %q: %s", kuid, err)
+	}
+
+	if err := updateUserProfileIDs(ctx, config, kuid, expandStringSet(d.Get("profile_ids").(*schema.Set)), d.Get("refresh").(string)); err != nil {
+		return diag.Errorf("Error attaching profiles to Kuzzle user %q: %s", kuid, err)
+	}
+
+	d.SetId(kuid)
+	d.Set("previous_profile_ids", previous)
+	config.summary.created()
+
+	return resourceKuzzleUserProfileAttachmentRead(ctx, d, meta)
+}
+
+// The rest of the user is not read: only its profiles are checked for drift
+func resourceKuzzleUserProfileAttachmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	profileIDs, err := userProfileIDs(ctx, config, d.Id())
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Error reading Kuzzle user %q profiles: %s", d.Id(), err)
+	}
+
+	d.Set("kuid", d.Id())
+	if err := d.Set("profile_ids", profileIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceKuzzleUserProfileAttachmentUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	if d.HasChange("profile_ids") {
+		if err := updateUserProfileIDs(ctx, config, d.Id(), expandStringSet(d.Get("profile_ids").(*schema.Set)), d.Get("refresh").(string)); err != nil {
+			return diag.Errorf("Error updating Kuzzle user %q profiles: %s", d.Id(), err)
+		}
+		config.summary.updated()
+	}
+
+	return resourceKuzzleUserProfileAttachmentRead(ctx, d, meta)
+}
+
+// The user is left in place with the profiles it had before the attachment, as a user needs at least one profile.
+// Nothing is done when they are unknown, e.g. after an import.
+func resourceKuzzleUserProfileAttachmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+
+	if previous := expandStringSet(d.Get("previous_profile_ids").(*schema.Set)); len(previous) > 0 {
+		err := updateUserProfileIDs(ctx, config, d.Id(), previous, d.Get("refresh").(string))
+		if err != nil && !isNotFound(err) {
+			return diag.Errorf("Error detaching profiles from Kuzzle user %q: %s", d.Id(), err)
+		}
+	}
+
+	d.SetId("")
+	config.summary.deleted()
+
+	return nil
+}
+
+// The profiles of the user before the attachment cannot be known: they are left as is when it is destroyed
+func resourceKuzzleUserProfileAttachmentImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("kuid", d.Id())
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// userProfileIDs returns the profiles assigned to a user
+func userProfileIDs(ctx context.Context, config *Config, kuid string) ([]string, error) {
+	var user struct {
+		Source struct {
+			ProfileIDs []string `json:"profileIds"`
+		} `json:"_source"`
+	}
+	if err := config.query(ctx, http.MethodGet, userPath(kuid), nil, &user); err != nil {
+		return nil, err
+	}
+
+	return user.Source.ProfileIDs, nil
+}
+
+// updateUserProfileIDs replaces the profiles of a user, the rest of its content being left untouched
+func updateUserProfileIDs(ctx context.Context, config *Config, kuid string, profileIDs []string, refresh string) error {
+	path := userPath(kuid) + "/_update" + config.refreshQuery(refresh)
+
+	return config.query(ctx, http.MethodPut, path, map[string]interface{}{"profileIds": profileIDs}, nil)
+}
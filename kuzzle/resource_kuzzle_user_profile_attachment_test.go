@@ -0,0 +1,154 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleUserProfileAttachmentCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		wantErr      bool
+		wantPrevious []interface{}
+		mocks        []Mock
+	}{
+		{
+			name:         "Success",
+			wantErr:      false,
+			wantPrevious: []interface{}{"default"},
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/users/ada",
+					response:   json.RawMessage(`{"result": {"_id": "ada", "_source": {"profileIds": ["default"], "name": "Ada"}}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/users/ada/_update",
+					response:   json.RawMessage(`{"result": {"_id": "ada"}}`),
+				},
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/users/ada",
+					response:   json.RawMessage(`{"result": {"_id": "ada", "_source": {"profileIds": ["editor"], "name": "Ada"}}}`),
+				},
+			},
+		},
+		{
+			name:    "Unknown user",
+			wantErr: true,
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 404,
+					method:     "GET",
+					url:        "http://kuzzle:7512",
+					route:      "/users/ada",
+					response:   json.RawMessage(`{"error": {"id": "security.user.not_found", "message": "User \"ada\" not found."}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleUserProfileAttachment().Schema, map[string]interface{}{
+				"kuid":        "ada",
+				"profile_ids": []interface{}{"editor"},
+			})
+			diags := resourceKuzzleUserProfileAttachmentCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleUserProfileAttachmentCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if got := d.Get("previous_profile_ids").(*schema.Set).List(); len(tt.wantPrevious) > 0 && !reflect.DeepEqual(got, tt.wantPrevious) {
+				t.Errorf("resourceKuzzleUserProfileAttachmentCreate() previous_profile_ids = %v, want %v", got, tt.wantPrevious)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleUserProfileAttachmentCreate() pending mocks = %v", gock.Pending())
+			}
+		})
+	}
+}
+
+// Only the profiles are sent, so that the rest of the user stays with its owner
+func Test_resourceKuzzleUserProfileAttachmentUpdate(t *testing.T) {
+	defer gock.Off()
+	gock.New("http://kuzzle:7512").
+		Put("/users/ada/_update").
+		BodyString(`{"profileIds":["admin","editor"]}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"_id": "ada"}}`))
+	gock.New("http://kuzzle:7512").
+		Get("/users/ada").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"_id": "ada", "_source": {"profileIds": ["admin", "editor"]}}}`))
+
+	r := resourceKuzzleUserProfileAttachment()
+	d := updatedResourceData(t, r, "ada", map[string]interface{}{
+		"kuid":        "ada",
+		"profile_ids": []interface{}{"editor"},
+	}, map[string]interface{}{
+		"kuid":        "ada",
+		"profile_ids": []interface{}{"editor", "admin"},
+	})
+
+	if diags := resourceKuzzleUserProfileAttachmentUpdate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Errorf("resourceKuzzleUserProfileAttachmentUpdate() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzleUserProfileAttachmentUpdate() pending mocks = %v", gock.Pending())
+	}
+}
+
+func Test_resourceKuzzleUserProfileAttachmentDelete(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []interface{}
+		mocked   bool
+	}{
+		{name: "Previous profiles assigned back", previous: []interface{}{"default"}, mocked: true},
+		{name: "Previous profiles unknown", previous: nil, mocked: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			if tt.mocked {
+				gock.New("http://kuzzle:7512").
+					Put("/users/ada/_update").
+					BodyString(`{"profileIds":["default"]}`).
+					Reply(200).
+					JSON(json.RawMessage(`{"result": {"_id": "ada"}}`))
+			}
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleUserProfileAttachment().Schema, map[string]interface{}{
+				"kuid":        "ada",
+				"profile_ids": []interface{}{"editor"},
+			})
+			d.SetId("ada")
+			d.Set("previous_profile_ids", tt.previous)
+
+			if diags := resourceKuzzleUserProfileAttachmentDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+				t.Errorf("resourceKuzzleUserProfileAttachmentDelete() diags = %v", diags)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleUserProfileAttachmentDelete() pending mocks = %v", gock.Pending())
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_resourceKuzzleUserCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      map[string]interface{}
+		wantBody string
+		wantErr  bool
+		wantID   string
+	}{
+		{
+			name: "With local credentials",
+			raw: map[string]interface{}{
+				"kuid":        "ada",
+				"profile_ids": []interface{}{"editor", "default"},
+				"content":     `{"name": "Ada Lovelace"}`,
+				"local_credentials": []interface{}{
+					map[string]interface{}{"username": "ada", "password": "s3cr3t"},
+				},
+			},
+			wantBody: `{"content":{"name":"Ada Lovelace","profileIds":["default","editor"]},"credentials":{"local":{"password":"s3cr3t","username":"ada"}}}`,
+			wantErr:  false,
+			wantID:   "ada",
+		},
+		{
+			name: "Without credentials",
+			raw: map[string]interface{}{
+				"kuid":        "ada",
+				"profile_ids": []interface{}{"default"},
+			},
+			wantBody: `{"content":{"profileIds":["default"]}}`,
+			wantErr:  false,
+			wantID:   "ada",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.
+				New("http://kuzzle:7512").
+				Post("/users/ada/_create").
+				MatchParam("refresh", refreshWaitFor).
+				BodyString(tt.wantBody).
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"_id": "ada"}}`))
+			gock.
+				New("http://kuzzle:7512").
+				Get("/users/ada").
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"_id": "ada", "_source": {"profileIds": ["default"]}}}`))
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleUser().Schema, tt.raw)
+			diags := resourceKuzzleUserCreate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleUserCreate() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleUserCreate() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleUserCreate() did not send the expected user")
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleUserRead(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		wantErr        bool
+		wantID         string
+		wantContent    string
+		wantProfileIDs []string
+		mock           Mock
+	}{
+		{
+			name:           "Only the configured fields",
+			content:        `{"name": "Ada Lovelace"}`,
+			wantErr:        false,
+			wantID:         "ada",
+			wantContent:    `{"name":"Ada Lovelace (updated)"}`,
+			wantProfileIDs: []string{"default", "editor"},
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ada",
+				response: json.RawMessage(`{"result": {"_id": "ada", "_source": {
+					"profileIds": ["editor", "default"],
+					"name": "Ada Lovelace (updated)",
+					"team": "engines",
+					"_kuzzle_info": {"author": "admin", "createdAt": 1617235200000}
+				}}}`),
+			},
+		},
+		{
+			name:           "Everything on import",
+			content:        "",
+			wantErr:        false,
+			wantID:         "ada",
+			wantContent:    `{"name":"Ada Lovelace","team":"engines"}`,
+			wantProfileIDs: []string{"default"},
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ada",
+				response: json.RawMessage(`{"result": {"_id": "ada", "_source": {
+					"profileIds": ["default"],
+					"name": "Ada Lovelace",
+					"team": "engines",
+					"_kuzzle_info": {"author": "admin", "createdAt": 1617235200000}
+				}}}`),
+			},
+		},
+		{
+			name:    "Deleted outside of Terraform",
+			wantErr: false,
+			wantID:  "",
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "GET",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ada",
+				response:   json.RawMessage(`{"error": {"id": "security.user.not_found", "message": "User \"ada\" not found."}}`),
+			},
+		},
+		{
+			name:    "Connection error",
+			wantErr: true,
+			wantID:  "ada",
+			mock: Mock{
+				enabled: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleUser().Schema, map[string]interface{}{})
+			d.SetId("ada")
+			d.Set("content", tt.content)
+
+			diags := resourceKuzzleUserRead(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleUserRead() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceKuzzleUserRead() id = %v, want %v", d.Id(), tt.wantID)
+			}
+			if tt.wantContent == "" {
+				return
+			}
+
+			if got := d.Get("content").(string); got != tt.wantContent {
+				t.Errorf("resourceKuzzleUserRead() content = %v, want %v", got, tt.wantContent)
+			}
+			if got := expandStringSet(d.Get("profile_ids").(*schema.Set)); !reflect.DeepEqual(got, tt.wantProfileIDs) {
+				t.Errorf("resourceKuzzleUserRead() profile_ids = %v, want %v", got, tt.wantProfileIDs)
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleUserUpdate(t *testing.T) {
+	old := map[string]interface{}{
+		"kuid":        "ada",
+		"profile_ids": []interface{}{"default"},
+		"content":     `{"name": "Ada Lovelace", "team": "engines"}`,
+		"local_credentials": []interface{}{
+			map[string]interface{}{"username": "ada", "password": "s3cr3t"},
+		},
+	}
+	tests := []struct {
+		name  string
+		new   map[string]interface{}
+		mocks []Mock
+	}{
+		{
+			name: "Content and profiles",
+			new: map[string]interface{}{
+				"kuid":        "ada",
+				"profile_ids": []interface{}{"default", "editor"},
+				"content":     `{"name": "Ada Lovelace"}`,
+				"local_credentials": []interface{}{
+					map[string]interface{}{"username": "ada", "password": "s3cr3t"},
+				},
+			},
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/users/ada/_update",
+					response:   json.RawMessage(`{"result": {"_id": "ada"}}`),
+				},
+			},
+		},
+		{
+			name: "Password changed",
+			new: map[string]interface{}{
+				"kuid":        "ada",
+				"profile_ids": []interface{}{"default"},
+				"content":     `{"name": "Ada Lovelace", "team": "engines"}`,
+				"local_credentials": []interface{}{
+					map[string]interface{}{"username": "ada", "password": "n3w-s3cr3t"},
+				},
+			},
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "PUT",
+					url:        "http://kuzzle:7512",
+					route:      "/credentials/local/ada/_update",
+					response:   json.RawMessage(`{"result": {"username": "ada"}}`),
+				},
+			},
+		},
+		{
+			name: "Credentials removed",
+			new: map[string]interface{}{
+				"kuid":        "ada",
+				"profile_ids": []interface{}{"default"},
+				"content":     `{"name": "Ada Lovelace", "team": "engines"}`,
+			},
+			mocks: []Mock{
+				{
+					enabled:    true,
+					statusCode: 200,
+					method:     "DELETE",
+					url:        "http://kuzzle:7512",
+					route:      "/credentials/local/ada",
+					response:   json.RawMessage(`{"result": {"acknowledged": true}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks(tt.mocks)
+			gock.
+				New("http://kuzzle:7512").
+				Get("/users/ada").
+				Reply(200).
+				JSON(json.RawMessage(`{"result": {"_id": "ada", "_source": {"profileIds": ["default"]}}}`))
+
+			d := updatedResourceData(t, resourceKuzzleUser(), "ada", old, tt.new)
+			if diags := resourceKuzzleUserUpdate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+				t.Fatalf("resourceKuzzleUserUpdate() diags = %v", diags)
+			}
+			if !gock.IsDone() {
+				t.Errorf("resourceKuzzleUserUpdate() pending mocks = %d, want none", len(gock.Pending()))
+			}
+		})
+	}
+}
+
+func Test_resourceKuzzleUserUpdateRemovedContent(t *testing.T) {
+	defer gock.Off()
+	gock.
+		New("http://kuzzle:7512").
+		Put("/users/ada/_update").
+		BodyString(`{"name":"Ada Lovelace","profileIds":["default"],"team":null}`).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"_id": "ada"}}`))
+	gock.
+		New("http://kuzzle:7512").
+		Get("/users/ada").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"_id": "ada", "_source": {"profileIds": ["default"], "name": "Ada Lovelace", "team": null}}}`))
+
+	d := updatedResourceData(t, resourceKuzzleUser(), "ada",
+		map[string]interface{}{"kuid": "ada", "profile_ids": []interface{}{"default"}, "content": `{"name": "Ada Lovelace", "team": "engines"}`},
+		map[string]interface{}{"kuid": "ada", "profile_ids": []interface{}{"default"}, "content": `{"name": "Ada Lovelace"}`},
+	)
+	if diags := resourceKuzzleUserUpdate(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"}); diags.HasError() {
+		t.Fatalf("resourceKuzzleUserUpdate() diags = %v", diags)
+	}
+	if !gock.IsDone() {
+		t.Errorf("resourceKuzzleUserUpdate() did not clear the removed content field")
+	}
+	if got := d.Get("content").(string); got != `{"name":"Ada Lovelace"}` {
+		t.Errorf("resourceKuzzleUserUpdate() content = %v, want the configured fields only", got)
+	}
+}
+
+func Test_resourceKuzzleUserDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+		mock    Mock
+	}{
+		{
+			name:    "Success",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 200,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ada",
+				response:   json.RawMessage(`{"result": {"_id": "ada"}}`),
+			},
+		},
+		{
+			name:    "Already deleted",
+			wantErr: false,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 404,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ada",
+				response:   json.RawMessage(`{"error": {"id": "security.user.not_found", "message": "User \"ada\" not found."}}`),
+			},
+		},
+		{
+			name:    "Forbidden",
+			wantErr: true,
+			mock: Mock{
+				enabled:    true,
+				statusCode: 403,
+				method:     "DELETE",
+				url:        "http://kuzzle:7512",
+				route:      "/users/ada",
+				response:   json.RawMessage(`{"error": {"message": "Forbidden action [security/deleteUser]"}}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			registerMocks([]Mock{tt.mock})
+
+			d := schema.TestResourceDataRaw(t, resourceKuzzleUser().Schema, map[string]interface{}{})
+			d.SetId("ada")
+
+			diags := resourceKuzzleUserDelete(context.Background(), d, &Config{Endpoint: "http://kuzzle:7512"})
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceKuzzleUserDelete() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package kuzzle
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// operationSummary counts what the provider did during a Terraform run.
+// The counters are kept in memory; when the summary has a path, they are written there
+// as JSON once the provider shuts down.
+type operationSummary struct {
+	mu   sync.Mutex
+	path string
+
+	Created  int `json:"created"`  // Kuzzle objects created
+	Updated  int `json:"updated"`  // Kuzzle objects updated
+	Deleted  int `json:"deleted"`  // Kuzzle objects deleted
+	Requests int `json:"requests"` // Requests sent to Kuzzle
+	Retries  int `json:"retries"`  // Requests sent again after a failure
+}
+
+// newOperationSummary returns a summary written to the given path on teardown, or nil if there is no path
+func newOperationSummary(path string) *operationSummary {
+	if path == "" {
+		return nil
+	}
+
+	s := &operationSummary{path: path}
+	onTeardown(s.write)
+
+	return s
+}
+
+func (s *operationSummary) created() { s.record(func() { s.Created++ }) }
+func (s *operationSummary) updated() { s.record(func() { s.Updated++ }) }
+func (s *operationSummary) deleted() { s.record(func() { s.Deleted++ }) }
+func (s *operationSummary) request() { s.record(func() { s.Requests++ }) }
+func (s *operationSummary) retry()   { s.record(func() { s.Retries++ }) }
+
+// record applies a change to the summary
+func (s *operationSummary) record(change func()) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change()
+}
+
+// write writes the summary down to its path, through a temporary file renamed once complete
+// so that the path never holds a partial summary
+func (s *operationSummary) write() {
+	s.mu.Lock()
+	content, err := json.MarshalIndent(s, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		log.Printf("[WARN] Cannot encode Kuzzle operation summary: %s", err)
+		return
+	}
+
+	if err := writeFileAtomically(s.path, content); err != nil {
+		log.Printf("[WARN] Cannot write Kuzzle operation summary to %s: %s", s.path, err)
+	}
+}
+
+// writeFileAtomically writes content to a temporary file next to path, then renames it to path
+func writeFileAtomically(path string, content []byte) error {
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
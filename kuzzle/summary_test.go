@@ -0,0 +1,103 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_operationSummary(t *testing.T) {
+	defer gock.Off()
+	gock.
+		New("http://kuzzle:7512").
+		Put("/profiles/editor/_createOrReplace").
+		Reply(429).
+		SetHeader("Retry-After", "1").
+		JSON(json.RawMessage(`{"error": {"message": "Too many requests"}}`))
+	gock.
+		New("http://kuzzle:7512").
+		Put("/profiles/editor/_createOrReplace").
+		Times(2).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"_id": "editor"}}`))
+	gock.
+		New("http://kuzzle:7512").
+		Get("/profiles/editor").
+		Times(2).
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"_id": "editor", "_source": {"policies": [{"roleId": "default"}]}}}`))
+	gock.
+		New("http://kuzzle:7512").
+		Delete("/profiles/editor").
+		Reply(200).
+		JSON(json.RawMessage(`{"result": {"_id": "editor"}}`))
+
+	path := filepath.Join(t.TempDir(), "summary.json")
+	config := &Config{
+		Endpoint:         "http://kuzzle:7512",
+		RateLimitMaxWait: time.Minute,
+		summary:          newOperationSummary(path),
+		sleep: func(ctx context.Context, d time.Duration) error {
+			return nil
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceKuzzleProfile().Schema, map[string]interface{}{
+		"profile_id": "editor",
+		"policy": []interface{}{
+			map[string]interface{}{"role_id": "default"},
+		},
+	})
+	if diags := resourceKuzzleProfileCreate(context.Background(), d, config); diags.HasError() {
+		t.Fatalf("resourceKuzzleProfileCreate() diags = %v", diags)
+	}
+	if diags := resourceKuzzleProfileUpdate(context.Background(), d, config); diags.HasError() {
+		t.Fatalf("resourceKuzzleProfileUpdate() diags = %v", diags)
+	}
+	if diags := resourceKuzzleProfileDelete(context.Background(), d, config); diags.HasError() {
+		t.Fatalf("resourceKuzzleProfileDelete() diags = %v", diags)
+	}
+
+	// The summary is only written once the provider shuts down
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("summary file was written before the teardown: %v", err)
+	}
+	Teardown()
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("summary file was not written: %v", err)
+	}
+	if files, _ := ioutil.ReadDir(filepath.Dir(path)); len(files) != 1 {
+		t.Errorf("summary directory holds %d files, want only the summary", len(files))
+	}
+
+	var got map[string]int
+	if err := json.Unmarshal(content, &got); err != nil {
+		t.Fatalf("summary file is not valid JSON: %v", err)
+	}
+	want := map[string]int{"created": 1, "updated": 1, "deleted": 1, "requests": 6, "retries": 1}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("summary %s = %d, want %d", key, got[key], value)
+		}
+	}
+}
+
+func Test_operationSummaryDisabled(t *testing.T) {
+	summary := newOperationSummary("")
+	if summary != nil {
+		t.Fatalf("newOperationSummary() = %v, want nil", summary)
+	}
+
+	// Counting on a disabled summary is a no-op
+	summary.created()
+	summary.request()
+}
@@ -0,0 +1,31 @@
+package kuzzle
+
+import "sync"
+
+// teardowns are the cleanups to run once Terraform is done with the provider
+var (
+	teardownsMu sync.Mutex
+	teardowns   []func()
+)
+
+// onTeardown registers a cleanup to run when the provider shuts down
+func onTeardown(cleanup func()) {
+	teardownsMu.Lock()
+	defer teardownsMu.Unlock()
+
+	teardowns = append(teardowns, cleanup)
+}
+
+// Teardown runs the registered cleanups, in reverse order of registration.
+// It is meant to be called once the plugin server has stopped, as Terraform
+// leaves a short grace period to the provider process before killing it.
+func Teardown() {
+	teardownsMu.Lock()
+	cleanups := teardowns
+	teardowns = nil
+	teardownsMu.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+}
@@ -0,0 +1,143 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// KuzzleClient sends requests to the Kuzzle API by controller and action, whatever the protocol used to reach it.
+// Args are the request arguments other than the body, e.g. the authentication strategy of auth:login.
+// The result part of the response is returned, Kuzzle errors being reported as *apiError.
+type KuzzleClient interface {
+	Query(ctx context.Context, controller string, action string, args map[string]string, body interface{}) (json.RawMessage, error)
+}
+
+// apiRoute is the HTTP route of a Kuzzle API action
+type apiRoute struct {
+	method    string
+	path      string // Route path, its :name segments being replaced by the matching arguments
+	retryable bool   // Whether the request can be sent again after a transient failure
+}
+
+// apiRoutes are the HTTP routes of the actions used by the provider, other actions are sent to the generic /_query route.
+// Logging in has no other effect than issuing a token, it is retried like idempotent requests.
+var apiRoutes = map[string]apiRoute{
+	"server:healthCheck": {method: http.MethodGet, path: "/_healthcheck", retryable: true},
+	"server:info":        {method: http.MethodGet, path: "/_serverInfo", retryable: true},
+	"server:now":         {method: http.MethodGet, path: "/_now", retryable: true},
+	"auth:checkToken":    {method: http.MethodPost, path: "/_checkToken"},
+	"auth:login":         {method: http.MethodPost, path: "/_login/:strategy", retryable: true},
+}
+
+// Query sends a request to the Kuzzle API, over WebSocket for ws and wss endpoints and over HTTP otherwise.
+// Unlike query, it neither logs in again when the token has expired nor waits when rate limited,
+// which suits the checks made while configuring the provider.
+func (c *Config) Query(ctx context.Context, controller string, action string, args map[string]string, body interface{}) (json.RawMessage, error) {
+	if isWebSocketEndpoint(c.Endpoint) {
+		return webSocketTransport{config: c}.query(ctx, controller, action, args, body)
+	}
+
+	method, path, retryable, body := c.route(controller, action, args, body)
+	resp, err := c.sendWithRetries(ctx, method, path, body, retryable)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp.StatusCode, respBody)
+	}
+
+	var response kuzzleResponse
+	if err := decodeJSON(respBody, &response); err != nil {
+		return nil, fmt.Errorf("Kuzzle returned an invalid response: %s", err)
+	}
+
+	return response.Result, nil
+}
+
+// route returns the HTTP request of an API action: its method, path and body, and whether it can be retried.
+// The arguments missing from the route path are sent in its query string.
+func (c *Config) route(controller string, action string, args map[string]string, body interface{}) (string, string, bool, interface{}) {
+	route, ok := apiRoutes[controller+":"+action]
+	if !ok {
+		request := map[string]interface{}{"controller": controller, "action": action}
+		for name, value := range args {
+			request[name] = value
+		}
+		if body != nil {
+			request["body"] = body
+		}
+
+		return http.MethodPost, "/_query", false, request
+	}
+
+	query := url.Values{}
+	for name, value := range args {
+		query.Set(name, value)
+	}
+
+	segments := strings.Split(route.path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			name := strings.TrimPrefix(segment, ":")
+			segments[i] = url.PathEscape(args[name])
+			query.Del(name)
+		}
+	}
+
+	path := strings.Join(segments, "/")
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	return route.method, path, route.retryable, body
+}
+
+// decodeResult decodes the result of a Kuzzle response into v, failing if it is missing or does not have the expected shape
+func decodeResult(result json.RawMessage, v interface{}) error {
+	if len(result) == 0 || string(result) == "null" {
+		return fmt.Errorf("Kuzzle response has no result")
+	}
+
+	if err := json.Unmarshal(result, v); err != nil {
+		return fmt.Errorf("Kuzzle response has an unexpected result: %s", result)
+	}
+
+	return nil
+}
+
+// isWebSocketEndpoint tells whether the endpoint is a ws or wss URL
+func isWebSocketEndpoint(endpoint string) bool {
+	URL, err := url.Parse(endpoint)
+
+	return err == nil && (URL.Scheme == "ws" || URL.Scheme == "wss")
+}
+
+// isSecureEndpoint tells whether the endpoint is an https or wss URL, reached over TLS
+func isSecureEndpoint(endpoint string) bool {
+	URL, err := url.Parse(endpoint)
+
+	return err == nil && (URL.Scheme == "https" || URL.Scheme == "wss")
+}
+
+// httpScheme returns the HTTP scheme matching a WebSocket one, other schemes being returned as is
+func httpScheme(scheme string) string {
+	switch scheme {
+	case "ws":
+		return "http"
+	case "wss":
+		return "https"
+	default:
+		return scheme
+	}
+}
@@ -0,0 +1,218 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"gopkg.in/h2non/gock.v1"
+)
+
+// fakeClient answers Kuzzle API requests with canned results or errors, keyed by controller:action
+type fakeClient struct {
+	results  map[string]string
+	errors   map[string]error
+	requests []string
+}
+
+func (f *fakeClient) Query(ctx context.Context, controller string, action string, args map[string]string, body interface{}) (json.RawMessage, error) {
+	name := controller + ":" + action
+	f.requests = append(f.requests, name)
+	if err, ok := f.errors[name]; ok {
+		return nil, err
+	}
+
+	return json.RawMessage(f.results[name]), nil
+}
+
+func Test_Config_route(t *testing.T) {
+	tests := []struct {
+		name          string
+		controller    string
+		action        string
+		args          map[string]string
+		body          interface{}
+		wantMethod    string
+		wantPath      string
+		wantRetryable bool
+		wantBody      interface{}
+	}{
+		{
+			name:          "Route",
+			controller:    "server",
+			action:        "healthCheck",
+			wantMethod:    http.MethodGet,
+			wantPath:      "/_healthcheck",
+			wantRetryable: true,
+		},
+		{
+			name:          "Path argument",
+			controller:    "auth",
+			action:        "login",
+			args:          map[string]string{"strategy": "oauth/github"},
+			body:          map[string]string{"code": "abc"},
+			wantMethod:    http.MethodPost,
+			wantPath:      "/_login/oauth%2Fgithub",
+			wantRetryable: true,
+			wantBody:      map[string]string{"code": "abc"},
+		},
+		{
+			name:          "Query string argument",
+			controller:    "auth",
+			action:        "login",
+			args:          map[string]string{"strategy": "local", "expiresIn": "1h"},
+			wantMethod:    http.MethodPost,
+			wantPath:      "/_login/local?expiresIn=1h",
+			wantRetryable: true,
+		},
+		{
+			name:       "Generic route",
+			controller: "collection",
+			action:     "exists",
+			args:       map[string]string{"index": "iot", "collection": "sensors"},
+			wantMethod: http.MethodPost,
+			wantPath:   "/_query",
+			wantBody: map[string]interface{}{
+				"controller": "collection",
+				"action":     "exists",
+				"index":      "iot",
+				"collection": "sensors",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Endpoint: "http://kuzzle:7512"}
+			method, path, retryable, body := config.route(tt.controller, tt.action, tt.args, tt.body)
+			if method != tt.wantMethod || path != tt.wantPath || retryable != tt.wantRetryable {
+				t.Errorf("route() = %s %s (retryable %v), want %s %s (retryable %v)", method, path, retryable, tt.wantMethod, tt.wantPath, tt.wantRetryable)
+			}
+			if !reflect.DeepEqual(body, tt.wantBody) {
+				t.Errorf("route() body = %v, want %v", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func Test_Config_Query(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		response   json.RawMessage
+		wantResult string
+		wantErr    string
+	}{
+		{
+			name:       "Result",
+			statusCode: 200,
+			response:   json.RawMessage(`{"status": 200, "result": true}`),
+			wantResult: "true",
+		},
+		{
+			name:       "Kuzzle error",
+			statusCode: 404,
+			response:   json.RawMessage(`{"status": 404, "error": {"id": "services.storage.unknown_index", "message": "Index \"iot\" does not exist.", "status": 404}}`),
+			wantErr:    `Kuzzle API error (404, services.storage.unknown_index): Index "iot" does not exist.`,
+		},
+		{
+			name:       "Invalid response",
+			statusCode: 200,
+			response:   json.RawMessage(`"ok"`),
+			wantErr:    "Kuzzle returned an invalid response: json: cannot unmarshal string into Go value of type kuzzle.kuzzleResponse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New("http://kuzzle:7512").
+				Post("/_query").
+				MatchHeader("Authorization", "^Bearer myToken$").
+				BodyString(`^\{"action":"exists","collection":"sensors","controller":"collection","index":"iot"\}$`).
+				Reply(tt.statusCode).
+				JSON(tt.response)
+
+			config := &Config{Endpoint: "http://kuzzle:7512", Token: "myToken"}
+			result, err := config.Query(context.Background(), "collection", "exists", map[string]string{"index": "iot", "collection": "sensors"}, nil)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Query() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if string(result) != tt.wantResult {
+				t.Errorf("Query() = %s, want %s", result, tt.wantResult)
+			}
+		})
+	}
+}
+
+func Test_checkHealth(t *testing.T) {
+	tests := []struct {
+		name         string
+		client       *fakeClient
+		wantErr      bool
+		wantRequests []string
+	}{
+		{
+			name:         "Healthy",
+			client:       &fakeClient{results: map[string]string{"server:healthCheck": `{"status": "green"}`}},
+			wantRequests: []string{"server:healthCheck"},
+		},
+		{
+			name:         "Unhealthy",
+			client:       &fakeClient{results: map[string]string{"server:healthCheck": `{"status": "red"}`}},
+			wantErr:      true,
+			wantRequests: []string{"server:healthCheck"},
+		},
+		{
+			name:         "No result",
+			client:       &fakeClient{},
+			wantErr:      true,
+			wantRequests: []string{"server:healthCheck"},
+		},
+		{
+			name: "No health check",
+			client: &fakeClient{errors: map[string]error{
+				"server:healthCheck": &apiError{StatusCode: 404, Message: "API URL not found"},
+				"server:now":         &apiError{StatusCode: 403, Message: "Forbidden"},
+			}},
+			wantRequests: []string{"server:healthCheck", "server:now"},
+		},
+		{
+			name: "No health check, unreachable",
+			client: &fakeClient{errors: map[string]error{
+				"server:healthCheck": &apiError{StatusCode: 404, Message: "API URL not found"},
+				"server:now":         &apiError{StatusCode: 503, Message: "Service Unavailable"},
+			}},
+			wantErr:      true,
+			wantRequests: []string{"server:healthCheck", "server:now"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkHealth(context.Background(), tt.client); (err != nil) != tt.wantErr {
+				t.Errorf("checkHealth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.client.requests, tt.wantRequests) {
+				t.Errorf("checkHealth() requests = %v, want %v", tt.client.requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func Test_authenticateClient(t *testing.T) {
+	client := &fakeClient{results: map[string]string{"auth:login": `{"_id": "admin", "jwt": "myJwt"}`}}
+
+	jwt, err := tryAuthenticate(context.Background(), client, "admin", "password")
+	if err != nil {
+		t.Fatalf("tryAuthenticate() error = %v", err)
+	}
+	if jwt != "myJwt" {
+		t.Errorf("tryAuthenticate() = %v, want %v", jwt, "myJwt")
+	}
+}
@@ -0,0 +1,185 @@
+package kuzzle
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// semver is a parsed semantic version. Build metadata is dropped, as it does not take part in comparisons.
+type semver struct {
+	core       [3]int   // Major, minor and patch numbers
+	preRelease []string // Dot separated pre-release identifiers, empty for a release
+}
+
+// parseVersion parses a semantic version, with an optional "v" prefix.
+// Missing minor and patch numbers default to 0, so that "2" and "2.4" are valid versions.
+func parseVersion(version string) (semver, error) {
+	var v semver
+
+	raw := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexByte(raw, '+'); i >= 0 {
+		raw = raw[:i]
+	}
+	if i := strings.IndexByte(raw, '-'); i >= 0 {
+		if raw[i+1:] == "" {
+			return v, fmt.Errorf("invalid version %q: empty pre-release", version)
+		}
+		v.preRelease = strings.Split(raw[i+1:], ".")
+		raw = raw[:i]
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q: expected at most 3 numbers", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q: %q is not a version number", version, part)
+		}
+		v.core[i] = n
+	}
+
+	for _, identifier := range v.preRelease {
+		if identifier == "" {
+			return v, fmt.Errorf("invalid version %q: empty pre-release identifier", version)
+		}
+	}
+
+	return v, nil
+}
+
+// compareVersions returns -1, 0 or 1 when version a is older than, the same as or newer than version b,
+// following the semantic versioning precedence: a pre-release comes before the release it leads to
+func compareVersions(a string, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range va.core {
+		if c := compareInts(va.core[i], vb.core[i]); c != 0 {
+			return c, nil
+		}
+	}
+
+	switch {
+	case len(va.preRelease) == 0 && len(vb.preRelease) == 0:
+		return 0, nil
+	case len(va.preRelease) == 0:
+		return 1, nil
+	case len(vb.preRelease) == 0:
+		return -1, nil
+	}
+
+	for i := 0; i < len(va.preRelease) && i < len(vb.preRelease); i++ {
+		if c := comparePreRelease(va.preRelease[i], vb.preRelease[i]); c != 0 {
+			return c, nil
+		}
+	}
+
+	return compareInts(len(va.preRelease), len(vb.preRelease)), nil
+}
+
+// comparePreRelease compares two pre-release identifiers: numeric ones are compared as numbers
+// and come before alphanumeric ones, which are compared as strings
+func comparePreRelease(a string, b string) int {
+	na, aErr := strconv.Atoi(a)
+	nb, bErr := strconv.Atoi(b)
+
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInts(na, nb)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// serverVersion returns the version of the Kuzzle server, fetched once and kept in the configuration.
+// An empty version is returned if it cannot be fetched, e.g. when server:info is denied to the provider user.
+func (c *Config) serverVersion(ctx context.Context) string {
+	if c.ServerVersion != "" {
+		return c.ServerVersion
+	}
+
+	version, err := fetchServerVersion(ctx, c)
+	if err != nil {
+		log.Printf("[WARN] Cannot fetch the Kuzzle server version: %s", err)
+		return ""
+	}
+
+	c.ServerVersion = version
+
+	return version
+}
+
+// requiresVersion declares the oldest Kuzzle version supporting a resource or data source:
+// creating or reading it fails with an explicit error when the server is older.
+// Nothing is checked when the server version is unknown, the server then rejects what it does not support.
+func requiresVersion(name string, minimum string, r *schema.Resource) *schema.Resource {
+	r.Description = fmt.Sprintf("Requires Kuzzle %s or later. ", minimum) + r.Description
+
+	if r.CreateContext != nil {
+		r.CreateContext = schema.CreateContextFunc(requireVersion(name, minimum, operation(r.CreateContext)))
+	}
+	if r.ReadContext != nil {
+		r.ReadContext = schema.ReadContextFunc(requireVersion(name, minimum, operation(r.ReadContext)))
+	}
+
+	return r
+}
+
+// requireVersion wraps an operation so that it fails when the Kuzzle server is older than the minimum version
+func requireVersion(name string, minimum string, op operation) operation {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if err := checkServerVersion(meta.(*Config).ServerVersion, name, minimum); err != nil {
+			return diag.FromErr(err)
+		}
+		return op(ctx, d, meta)
+	}
+}
+
+// checkServerVersion tells whether the server version satisfies the minimum version required by name.
+// Unknown or unparsable server versions are let through.
+func checkServerVersion(version string, name string, minimum string) error {
+	if version == "" {
+		return nil
+	}
+
+	c, err := compareVersions(version, minimum)
+	if err != nil {
+		log.Printf("[WARN] Cannot check the Kuzzle server version required by %s: %s", name, err)
+		return nil
+	}
+
+	if c < 0 {
+		return fmt.Errorf("%s requires Kuzzle >= %s, server is %s", name, minimum, version)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package kuzzle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_compareVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       string
+		b       string
+		want    int
+		wantErr bool
+	}{
+		{name: "Equal", a: "2.4.0", b: "2.4.0", want: 0},
+		{name: "Older major", a: "1.11.3", b: "2.4.0", want: -1},
+		{name: "Newer minor", a: "2.10.0", b: "2.4.0", want: 1},
+		{name: "Newer patch", a: "2.4.1", b: "2.4.0", want: 1},
+		{name: "Prefixed", a: "v2.4.0", b: "2.4.0", want: 0},
+		{name: "Missing patch", a: "2.4", b: "2.4.0", want: 0},
+		{name: "Missing minor", a: "2", b: "2.4.0", want: -1},
+		{name: "Build metadata ignored", a: "2.4.0+20210401", b: "2.4.0", want: 0},
+		{name: "Pre-release before release", a: "2.4.0-beta.1", b: "2.4.0", want: -1},
+		{name: "Pre-release after previous release", a: "2.4.0-beta.1", b: "2.3.9", want: 1},
+		{name: "Numeric pre-release identifiers", a: "2.4.0-beta.10", b: "2.4.0-beta.2", want: 1},
+		{name: "Alphanumeric pre-release identifiers", a: "2.4.0-alpha", b: "2.4.0-beta", want: -1},
+		{name: "Numeric before alphanumeric identifier", a: "2.4.0-1", b: "2.4.0-alpha", want: -1},
+		{name: "Longer pre-release", a: "2.4.0-beta.1", b: "2.4.0-beta", want: 1},
+		{name: "Empty", a: "", b: "2.4.0", wantErr: true},
+		{name: "Not a number", a: "2.x", b: "2.4.0", wantErr: true},
+		{name: "Too many numbers", a: "2.4.0.1", b: "2.4.0", wantErr: true},
+		{name: "Empty pre-release", a: "2.4.0-", b: "2.4.0", wantErr: true},
+		{name: "Empty pre-release identifier", a: "2.4.0-beta..1", b: "2.4.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compareVersions(tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compareVersions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("compareVersions() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkServerVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr string
+	}{
+		{name: "Newer", version: "2.10.4", wantErr: ""},
+		{name: "Same", version: "2.4.0", wantErr: ""},
+		{name: "Older", version: "1.11.3", wantErr: "kuzzle_api_key requires Kuzzle >= 2.4.0, server is 1.11.3"},
+		{name: "Pre-release of the minimum", version: "2.4.0-rc.1", wantErr: "kuzzle_api_key requires Kuzzle >= 2.4.0, server is 2.4.0-rc.1"},
+		{name: "Unknown", version: "", wantErr: ""},
+		{name: "Unparsable", version: "nightly", wantErr: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkServerVersion(tt.version, "kuzzle_api_key", "2.4.0")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkServerVersion() error = %v, want none", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("checkServerVersion() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_requiresVersion(t *testing.T) {
+	called := false
+	read := func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		called = true
+		return nil
+	}
+	r := requiresVersion("kuzzle_api_key", "2.4.0", &schema.Resource{ReadContext: read})
+
+	if diags := r.ReadContext(context.Background(), nil, &Config{ServerVersion: "1.11.3"}); !diags.HasError() || called {
+		t.Errorf("ReadContext() diags = %v, called = %v, want an error without reading", diags, called)
+	}
+	if diags := r.ReadContext(context.Background(), nil, &Config{ServerVersion: "2.10.4"}); diags.HasError() || !called {
+		t.Errorf("ReadContext() diags = %v, called = %v, want a read", diags, called)
+	}
+}
+
+func Test_Config_serverVersion(t *testing.T) {
+	defer gock.Off()
+	gock.New("http://kuzzle:7512").Get("/_serverInfo").Reply(403).JSON(map[string]interface{}{
+		"status": 403,
+		"error":  map[string]interface{}{"message": "Forbidden action [server/info]"},
+	})
+	mockServerInfo("2.10.4")
+
+	config := &Config{Endpoint: "http://kuzzle:7512"}
+	if got := config.serverVersion(context.Background()); got != "" {
+		t.Errorf("serverVersion() = %q, want an unknown version", got)
+	}
+	if got := config.serverVersion(context.Background()); got != "2.10.4" {
+		t.Errorf("serverVersion() = %q, want %q", got, "2.10.4")
+	}
+
+	// The version is only fetched once
+	if got := config.serverVersion(context.Background()); got != "2.10.4" {
+		t.Errorf("serverVersion() = %q, want %q", got, "2.10.4")
+	}
+}
@@ -0,0 +1,156 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"golang.org/x/net/websocket"
+)
+
+// webSocketTransport sends requests to the Kuzzle WebSocket API, each one over its own connection.
+// Requests are query objects naming the controller and action, answered by a response holding the same request id.
+type webSocketTransport struct {
+	config *Config
+}
+
+// query sends a request to the Kuzzle WebSocket API, on behalf of the authenticated user, and returns the result of its response.
+// Messages answering other requests, such as notifications, are skipped.
+func (t webSocketTransport) query(ctx context.Context, controller string, action string, args map[string]string, body interface{}) (json.RawMessage, error) {
+	requestID, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	request := map[string]interface{}{}
+	for name, value := range args {
+		request[name] = value
+	}
+	request["controller"] = controller
+	request["action"] = action
+	request["requestId"] = requestID
+	if body != nil {
+		request["body"] = body
+	}
+	if token := t.config.authToken(); token != "" {
+		request["jwt"] = token
+	}
+
+	conn, err := t.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks the pending send or receive when the context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := websocket.JSON.Send(conn, request); err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	for {
+		var message []byte
+		if err := websocket.Message.Receive(conn, &message); err != nil {
+			return nil, contextError(ctx, err)
+		}
+
+		var response struct {
+			RequestID string `json:"requestId"`
+			kuzzleResponse
+		}
+		if err := decodeJSON(message, &response); err != nil {
+			return nil, fmt.Errorf("Kuzzle returned an invalid response: %s", err)
+		}
+		if response.RequestID != requestID {
+			continue
+		}
+
+		if response.Status != http.StatusOK {
+			return nil, newAPIError(response.Status, message)
+		}
+
+		return response.Result, nil
+	}
+}
+
+// dial opens a WebSocket connection to the endpoint, with the custom headers, TLS settings
+// and time limit of the HTTP client
+func (t webSocketTransport) dial(ctx context.Context) (*websocket.Conn, error) {
+	location, err := url.Parse(t.config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	origin := *location
+	origin.Scheme = httpScheme(location.Scheme)
+	origin.Path, origin.RawPath = "", ""
+
+	wsConfig, err := websocket.NewConfig(location.String(), origin.String())
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range t.config.Headers {
+		wsConfig.Header.Set(name, value)
+	}
+	if t.config.RunID != "" && t.config.RunIDHeader != "" {
+		wsConfig.Header.Set(t.config.RunIDHeader, t.config.RunID)
+	}
+
+	client := t.config.httpClient()
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		wsConfig.TlsConfig = transport.TLSClientConfig
+	}
+
+	deadline, hasDeadline := ctx.Deadline()
+	if client.Timeout > 0 && (!hasDeadline || time.Now().Add(client.Timeout).Before(deadline)) {
+		deadline, hasDeadline = time.Now().Add(client.Timeout), true
+	}
+	if hasDeadline {
+		wsConfig.Dialer = &net.Dialer{Deadline: deadline}
+	}
+
+	conn, err := websocket.DialConfig(wsConfig)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+	if hasDeadline {
+		conn.SetDeadline(deadline)
+	}
+
+	return conn, nil
+}
+
+// contextError returns the error of the context if it is done, as it explains why the connection failed.
+// The connection deadline is the context one: its timeout can fire before the context is marked as done,
+// in which case it is reported as the context deadline.
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	var dialErr *websocket.DialError
+	if errors.As(err, &dialErr) {
+		err = dialErr.Err
+	}
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) && errors.Is(err, os.ErrDeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+
+	return err
+}
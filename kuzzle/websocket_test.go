@@ -0,0 +1,188 @@
+package kuzzle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/net/websocket"
+)
+
+// newWebSocketServer starts a Kuzzle WebSocket API answering each request with the reply of its action,
+// the request id being added to the reply. Requests whose action has no reply are left unanswered.
+func newWebSocketServer(t *testing.T, replies map[string]string, requests chan<- map[string]interface{}) *httptest.Server {
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		for {
+			var request map[string]interface{}
+			if err := websocket.JSON.Receive(conn, &request); err != nil {
+				return
+			}
+			if requests != nil {
+				requests <- request
+			}
+
+			reply, ok := replies[request["controller"].(string)+":"+request["action"].(string)]
+			if !ok {
+				continue
+			}
+
+			// A notification of another room comes first, it must be skipped
+			websocket.Message.Send(conn, `{"room": "notifications", "requestId": "other", "status": 200, "result": {}}`)
+
+			var response map[string]interface{}
+			if err := json.Unmarshal([]byte(reply), &response); err != nil {
+				t.Errorf("invalid reply %s: %s", reply, err)
+				return
+			}
+			response["requestId"] = request["requestId"]
+			websocket.JSON.Send(conn, response)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func webSocketEndpoint(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func Test_webSocketTransport_checkConnection(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies map[string]string
+		wantErr string
+	}{
+		{
+			name:    "Healthy",
+			replies: map[string]string{"server:healthCheck": `{"status": 200, "result": {"status": "green"}}`},
+		},
+		{
+			name:    "Unhealthy",
+			replies: map[string]string{"server:healthCheck": `{"status": 200, "result": {"status": "red"}}`},
+			wantErr: `status is "red"`,
+		},
+		{
+			name: "No healthcheck action",
+			replies: map[string]string{
+				"server:healthCheck": `{"status": 404, "error": {"id": "api.process.action_not_found", "message": "API action \"server\":\"healthCheck\" not found", "status": 404}}`,
+				"server:now":         `{"status": 200, "result": {"now": 1618401035374}}`,
+			},
+		},
+		{
+			name:    "Service unavailable",
+			replies: map[string]string{"server:healthCheck": `{"status": 503, "error": {"id": "core.overloaded", "message": "Kuzzle is overloaded", "status": 503}}`},
+			wantErr: "Kuzzle is overloaded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newWebSocketServer(t, tt.replies, nil)
+
+			err := checkConnection(context.Background(), &Config{Endpoint: webSocketEndpoint(server)})
+			if (err != nil) != (tt.wantErr != "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("checkConnection() error = %v, wantErr %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_webSocketTransport_checkToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		reply       string
+		wantRequest string
+		wantErr     string
+	}{
+		{
+			name:        "Valid token in body",
+			mode:        tokenCheckToken,
+			reply:       `{"status": 200, "result": {"valid": true}}`,
+			wantRequest: `"body":{"token":"myApiKey"}`,
+		},
+		{
+			name:        "Valid token in request",
+			mode:        tokenCheckHeader,
+			reply:       `{"status": 200, "result": {"valid": true}}`,
+			wantRequest: `"jwt":"myApiKey"`,
+		},
+		{
+			name:        "Invalid token",
+			mode:        tokenCheckJWT,
+			reply:       `{"status": 200, "result": {"valid": false, "state": "Invalid token"}}`,
+			wantRequest: `"body":{"jwt":"myApiKey"}`,
+			wantErr:     "API key is invalid",
+		},
+		{
+			name:        "No validity flag",
+			mode:        tokenCheckToken,
+			reply:       `{"status": 200, "result": {}}`,
+			wantRequest: `"body":{"token":"myApiKey"}`,
+			wantErr:     "no validity flag",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := make(chan map[string]interface{}, 1)
+			server := newWebSocketServer(t, map[string]string{"auth:checkToken": tt.reply}, requests)
+
+			config := &Config{Endpoint: webSocketEndpoint(server), TokenCheckMode: tt.mode}
+			err := checkToken(context.Background(), config, "myApiKey", tokenKindAPIKey)
+			if (err != nil) != (tt.wantErr != "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("checkToken() error = %v, wantErr %q", err, tt.wantErr)
+			}
+
+			request, _ := json.Marshal(<-requests)
+			if !strings.Contains(string(request), tt.wantRequest) {
+				t.Errorf("checkToken() request = %s, want %s", request, tt.wantRequest)
+			}
+		})
+	}
+}
+
+func Test_webSocketTransport_queryCancelled(t *testing.T) {
+	server := newWebSocketServer(t, map[string]string{}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := checkConnection(ctx, &Config{Endpoint: webSocketEndpoint(server)})
+	if err != context.DeadlineExceeded {
+		t.Errorf("checkConnection() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func Test_providerConfigureWebSocket(t *testing.T) {
+	requests := make(chan map[string]interface{}, 4)
+	server := newWebSocketServer(t, map[string]string{
+		"server:healthCheck": `{"status": 200, "result": {"status": "green"}}`,
+		"server:info":        `{"status": 200, "result": {"serverInfo": {"kuzzle": {"version": "2.11.0"}}}}`,
+		"auth:checkToken":    `{"status": 200, "result": {"valid": true}}`,
+	}, requests)
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"endpoint": webSocketEndpoint(server),
+		"api_key":  "myApiKey",
+	})
+	config, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("providerConfigure() diags = %v", diags)
+	}
+	if token := config.(*Config).Token; token != "myApiKey" {
+		t.Errorf("providerConfigure() token = %q, want %q", token, "myApiKey")
+	}
+
+	var actions []string
+	for len(requests) > 0 {
+		request := <-requests
+		actions = append(actions, request["controller"].(string)+":"+request["action"].(string))
+	}
+	if got := strings.Join(actions, ","); got != "server:healthCheck,server:info,auth:checkToken" {
+		t.Errorf("providerConfigure() sent %s over WebSocket", got)
+	}
+}
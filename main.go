@@ -9,4 +9,6 @@ func main() {
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: kuzzle.Provider,
 	})
+
+	kuzzle.Teardown()
 }